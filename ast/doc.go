@@ -55,9 +55,9 @@
 //
 //
 //	                    rpcDecl (*RPCDeclNode)
-//	                /        |    |       |     \
-//	         RPCService Ident '{' rpcMethods '}'
-//	                                  /  ..  \
+//	                /        |     |      |      |     \
+//	         RPCService Ident metadata '{' rpcMethods '}'
+//	                                           /  ..  \
 //	                         rpcMethod (*RPCMethodNode)
 //	                    / |     |    |   |     |      |     \
 //	              Ident '(' Ident ')' ':' Ident metadata ';'
@@ -84,4 +84,11 @@
 //	                (*PositiveUintLiteralNode)   (*SignedFloatLiteralNode)
 //
 // Note: types without '*' are all `interface`s.
+//
+// Walk, Inspect and WalkPrePost traverse a syntax tree in depth-first order, in the manner
+// of package go/ast. SimpleVisitor dispatches the visited nodes to typed callbacks.
+//
+// The syntax tree of a parsed file is available as SchemaDesc.Schema of package fbs.
+// Exported identifiers of this package follow the same compatibility promise as package
+// fbs: they will not be removed or changed incompatibly within the same major version.
 package ast
@@ -78,7 +78,9 @@ func WithEnumTypeName(typeName *TypeNameNode) EnumDeclOption {
 func WithEnumMetadata(metadata *MetadataNode) EnumDeclOption {
 	return func(node *EnumDeclNode) {
 		node.Metadata = metadata
-		node.compositeNode.children = append(node.compositeNode.children, metadata)
+		if metadata != nil {
+			node.compositeNode.children = append(node.compositeNode.children, metadata)
+		}
 	}
 }
 
@@ -121,24 +123,37 @@ func NewEnumDeclNode(opts ...EnumDeclOption) *EnumDeclNode {
 //
 // Red = 0
 // Green
+// Blue (deprecated)
 type EnumValueNode struct {
 	compositeNode
-	Name   *IdentNode
-	Equal  *RuneNode
-	IntVal IntValueNode
+	Name     *IdentNode
+	Equal    *RuneNode
+	IntVal   IntValueNode
+	Metadata *MetadataNode
+	Comma    *RuneNode // The comma following the value, could be nil.
 }
 
-// NewEnumValueNode creates value node for enum or union.
-func NewEnumValueNode(name *IdentNode, equal *RuneNode, intVal IntValueNode) *EnumValueNode {
+// NewEnumValueNode creates value node for enum or union. Note: metadata could be nil.
+func NewEnumValueNode(name *IdentNode, equal *RuneNode, intVal IntValueNode, metadata *MetadataNode) *EnumValueNode {
 	var children []Node
 	children = append(children, name)
 	if equal != nil {
 		children = append(children, equal, intVal)
 	}
+	if metadata != nil {
+		children = append(children, metadata)
+	}
 	return &EnumValueNode{
 		compositeNode: compositeNode{children: children},
 		Name:          name,
 		Equal:         equal,
 		IntVal:        intVal,
+		Metadata:      metadata,
 	}
 }
+
+// AddComma sets the comma following the value.
+func (e *EnumValueNode) AddComma(comma *RuneNode) {
+	e.Comma = comma
+	e.compositeNode.children = append(e.compositeNode.children, comma)
+}
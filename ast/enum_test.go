@@ -17,7 +17,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestEnum(t *testing.T) {
@@ -39,7 +39,7 @@ func TestEnum(t *testing.T) {
 	closeBrace := ast.NewRuneNode('}', ast.Token{})
 	equal := ast.NewRuneNode('=', ast.Token{})
 	intVal := ast.NewUintLiteralNode(10, ast.Token{})
-	enumVal := ast.NewEnumValueNode(name, equal, intVal)
+	enumVal := ast.NewEnumValueNode(name, equal, intVal, metadata)
 	enums := []*ast.EnumValueNode{enumVal}
 	var opts []ast.EnumDeclOption
 	opts = append(opts, ast.WithEnumKeyword(keyword))
@@ -61,4 +61,5 @@ func TestEnum(t *testing.T) {
 	assert.Equal(t, openBrace, enumDecl.OpenBrace)
 	assert.Equal(t, enums, enumDecl.Decls)
 	assert.Equal(t, closeBrace, enumDecl.CloseBrace)
+	assert.Equal(t, metadata, enumVal.Metadata)
 }
@@ -17,7 +17,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestIdent(t *testing.T) {
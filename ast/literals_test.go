@@ -18,7 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestLiterals(t *testing.T) {
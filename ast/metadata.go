@@ -35,6 +35,7 @@ func NewMetadataNode(openParen *RuneNode, entries []*MetadataEntryNode, closePar
 	for _, entry := range entries {
 		children = append(children, entry)
 	}
+	children = append(children, closeParen)
 	return &MetadataNode{
 		compositeNode: compositeNode{children: children},
 		OpenParen:     openParen,
@@ -43,6 +44,20 @@ func NewMetadataNode(openParen *RuneNode, entries []*MetadataEntryNode, closePar
 	}
 }
 
+// Entry returns the last entry whose key is the given key, nil if not found.
+// It is safe to call on nil.
+func (n *MetadataNode) Entry(key string) *MetadataEntryNode {
+	if n == nil {
+		return nil
+	}
+	for i := len(n.Entries) - 1; i >= 0; i-- {
+		if n.Entries[i].Key.Val == key {
+			return n.Entries[i]
+		}
+	}
+	return nil
+}
+
 // MetadataEntryNode represents an entry of metadata. It can be a key-value pair
 // or just key. Example:
 //
@@ -56,6 +71,7 @@ type MetadataEntryNode struct {
 	Key   *IdentNode
 	Colon *RuneNode
 	Value ValueNode
+	Comma *RuneNode // The comma following the entry, nil for the last entry.
 }
 
 // NewMetadataEntryNode creates an entry for metadata node.
@@ -72,3 +88,9 @@ func NewMetadataEntryNode(key *IdentNode, colon *RuneNode, value ValueNode) *Met
 		Value:         value,
 	}
 }
+
+// AddComma sets the comma following the entry.
+func (m *MetadataEntryNode) AddComma(comma *RuneNode) {
+	m.Comma = comma
+	m.compositeNode.children = append(m.compositeNode.children, comma)
+}
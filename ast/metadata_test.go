@@ -17,7 +17,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestMetadata(t *testing.T) {
@@ -31,5 +31,10 @@ func TestMetadata(t *testing.T) {
 	metadata := ast.NewMetadataNode(openParen, entries, closeParen)
 	assert.Equal(t, openParen, metadata.OpenParen)
 	assert.Equal(t, closeParen, metadata.CloseParen)
+	assert.Equal(t, closeParen, metadata.Children()[len(metadata.Children())-1])
 	assert.Equal(t, entries, metadata.Entries)
+	assert.Equal(t, metadataEntry, metadata.Entry("mykey"))
+	assert.Nil(t, metadata.Entry("otherkey"))
+	var nilMetadata *ast.MetadataNode
+	assert.Nil(t, nilMetadata.Entry("mykey"))
 }
@@ -13,6 +13,8 @@
 
 package ast
 
+import "trpc.group/trpc-go/fbs/internal/astinternal"
+
 // Node is the interface that will be implemented by all nodes in the AST.
 type Node interface {
 	Start() *Position
@@ -23,6 +25,8 @@ type Node interface {
 type TerminalNode interface {
 	Node
 	RawText() string
+	LeadingComments() []Comment
+	TrailingComments() []Comment
 }
 
 var _ TerminalNode = (*StringLiteralNode)(nil)
@@ -31,12 +35,20 @@ var _ TerminalNode = (*FloatLiteralNode)(nil)
 var _ TerminalNode = (*IdentNode)(nil)
 var _ TerminalNode = (*RuneNode)(nil)
 
+// CompositeNode should be implemented by all non-terminal node types.
+type CompositeNode interface {
+	Node
+	Children() []Node
+}
+
 // terminalNode represents all terminal tokens. It records
 // position information and implements the TerminalNode
 // interface.
 type terminalNode struct {
-	posRange PosRange
-	raw      string
+	posRange         PosRange
+	raw              string
+	leadingComments  []Comment
+	trailingComments []Comment
 }
 
 // Start implements Node interface, providing the start of the span.
@@ -54,6 +66,28 @@ func (t *terminalNode) RawText() string {
 	return t.raw
 }
 
+// LeadingComments returns the comments between the previous token and this one.
+func (t *terminalNode) LeadingComments() []Comment {
+	return t.leadingComments
+}
+
+// TrailingComments returns the comments that follow this token on the same line.
+func (t *terminalNode) TrailingComments() []Comment {
+	return t.trailingComments
+}
+
+func (t *terminalNode) setTrailingComments(comments []Comment) {
+	t.trailingComments = comments
+}
+
+func init() {
+	astinternal.SetTrailingComments = func(n interface{}, comments interface{}) {
+		if t, ok := n.(interface{ setTrailingComments([]Comment) }); ok {
+			t.setTrailingComments(comments.([]Comment))
+		}
+	}
+}
+
 // compositeNode represents all nodes that are not terminals.
 // Typically it has children.
 type compositeNode struct {
@@ -77,15 +111,55 @@ func (c *compositeNode) Children() []Node {
 
 // Token stores information of a token from lexer.
 type Token struct {
-	PosRange        // Location of the token in the source file.
-	RawText  string // Raw text of the token.
+	PosRange                   // Location of the token in the source file.
+	RawText          string    // Raw text of the token.
+	LeadingComments  []Comment // Comments between the previous token and this one.
+	TrailingComments []Comment // Comments following the token on the same line.
 }
 
 // asTerminalNode create a terminalNode out of a Token.
 func (t *Token) asTerminalNode() terminalNode {
 	return terminalNode{
-		posRange: t.PosRange,
-		raw:      t.RawText,
+		posRange:         t.PosRange,
+		raw:              t.RawText,
+		leadingComments:  t.LeadingComments,
+		trailingComments: t.TrailingComments,
+	}
+}
+
+// FirstToken returns the first terminal node of the given node, nil if there is none.
+func FirstToken(n Node) TerminalNode {
+	for {
+		switch t := n.(type) {
+		case TerminalNode:
+			return t
+		case CompositeNode:
+			children := t.Children()
+			if len(children) == 0 {
+				return nil
+			}
+			n = children[0]
+		default:
+			return nil
+		}
+	}
+}
+
+// LastToken returns the last terminal node of the given node, nil if there is none.
+func LastToken(n Node) TerminalNode {
+	for {
+		switch t := n.(type) {
+		case TerminalNode:
+			return t
+		case CompositeNode:
+			children := t.Children()
+			if len(children) == 0 {
+				return nil
+			}
+			n = children[len(children)-1]
+		default:
+			return nil
+		}
 	}
 }
 
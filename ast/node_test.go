@@ -17,7 +17,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
+	"trpc.group/trpc-go/fbs/internal/astinternal"
 )
 
 func TestNode(t *testing.T) {
@@ -48,3 +49,25 @@ func TestNode(t *testing.T) {
 	children := metadata.Children()
 	assert.Equal(t, 3, len(children))
 }
+
+func TestFirstToken(t *testing.T) {
+	comments := []ast.Comment{{Text: "/// doc"}}
+	name := ast.NewIdentNode("key", ast.Token{LeadingComments: comments})
+	value := ast.NewIdentNode("value", ast.Token{})
+	colon := ast.NewRuneNode(':', ast.Token{})
+	entry := ast.NewMetadataEntryNode(name, colon, value)
+	assert.Equal(t, name, ast.FirstToken(entry))
+	assert.Equal(t, value, ast.LastToken(entry))
+	assert.Equal(t, comments, ast.FirstToken(entry).LeadingComments())
+	assert.Nil(t, value.LeadingComments())
+}
+
+func TestTrailingComments(t *testing.T) {
+	comments := []ast.Comment{{Text: "// trailing"}}
+	semicolon := ast.NewRuneNode(';', ast.Token{})
+	assert.Nil(t, semicolon.TrailingComments())
+	astinternal.SetTrailingComments(semicolon, comments)
+	assert.Equal(t, comments, semicolon.TrailingComments())
+	ident := ast.NewIdentNode("ident", ast.Token{TrailingComments: comments})
+	assert.Equal(t, comments, ident.TrailingComments())
+}
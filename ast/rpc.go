@@ -15,7 +15,7 @@ package ast
 
 // RPCDeclNode represents a rpc service statement. Example:
 //
-//	rpc_service MonsterStorage {
+//	rpc_service MonsterStorage (private) {
 //	  Store(Monster):Stat (streaming: "none");
 //	  Retrieve(Stat):Monster (streaming: "server", idempotent);
 //	  GetMaxHitPoint(Monster):Stat (streaming: "client");
@@ -25,6 +25,7 @@ type RPCDeclNode struct {
 	compositeNode
 	Keyword    *KeywordNode
 	Name       *IdentNode
+	Metadata   *MetadataNode
 	OpenBrace  *RuneNode
 	Methods    []*RPCMethodNode
 	CloseBrace *RuneNode
@@ -33,18 +34,24 @@ type RPCDeclNode struct {
 // AsDeclElement implements DeclElement interface.
 func (*RPCDeclNode) AsDeclElement() {}
 
-// NewRPCDeclNode creates a RPC service declaration node.
-func NewRPCDeclNode(keyword *KeywordNode, name *IdentNode, openBrace *RuneNode,
+// NewRPCDeclNode creates a RPC service declaration node. Note: metadata could be nil.
+func NewRPCDeclNode(keyword *KeywordNode, name *IdentNode, metadata *MetadataNode, openBrace *RuneNode,
 	methods []*RPCMethodNode, closeBrace *RuneNode) *RPCDeclNode {
 	var children []Node
-	children = append(children, keyword, name, openBrace)
+	children = append(children, keyword, name)
+	if metadata != nil {
+		children = append(children, metadata)
+	}
+	children = append(children, openBrace)
 	for _, method := range methods {
 		children = append(children, method)
 	}
+	children = append(children, closeBrace)
 	return &RPCDeclNode{
 		compositeNode: compositeNode{children: children},
 		Keyword:       keyword,
 		Name:          name,
+		Metadata:      metadata,
 		OpenBrace:     openBrace,
 		Methods:       methods,
 		CloseBrace:    closeBrace,
@@ -126,7 +133,9 @@ func WithMethodRspName(name IdentLiteralElement) MethodOption {
 func WithMethodMetadata(metadata *MetadataNode) MethodOption {
 	return func(node *RPCMethodNode) {
 		node.Metadata = metadata
-		node.compositeNode.children = append(node.compositeNode.children, metadata)
+		if metadata != nil {
+			node.compositeNode.children = append(node.compositeNode.children, metadata)
+		}
 	}
 }
 
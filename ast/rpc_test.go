@@ -17,7 +17,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestRPC(t *testing.T) {
@@ -45,10 +45,12 @@ func TestRPC(t *testing.T) {
 	opts = append(opts, ast.WithMethodSemicolon(semicolon))
 	rpcMethod := ast.NewRPCMethodNode(opts...)
 	methods := []*ast.RPCMethodNode{rpcMethod}
-	rpcDecl := ast.NewRPCDeclNode(keyword, name, openBrace, methods, closeBrace)
+	rpcDecl := ast.NewRPCDeclNode(keyword, name, metadata, openBrace, methods, closeBrace)
 	rpcDecl.AsDeclElement()
 	assert.Equal(t, name, rpcDecl.Name)
+	assert.Equal(t, metadata, rpcDecl.Metadata)
 	assert.Equal(t, openBrace, rpcDecl.OpenBrace)
 	assert.Equal(t, methods, rpcDecl.Methods)
 	assert.Equal(t, closeBrace, rpcDecl.CloseBrace)
+	assert.Equal(t, closeBrace, rpcDecl.Children()[len(rpcDecl.Children())-1])
 }
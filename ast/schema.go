@@ -19,6 +19,9 @@ type SchemaNode struct {
 	compositeNode
 	Includes []*IncludeNode
 	Decls    []DeclElement
+	// EOF is the end-of-file token, whose leading comments are the comments after the last
+	// declaration. It is set by the parser and is not one of the children of the node.
+	EOF *RuneNode
 }
 
 // NewSchemaNode creates a new schema node.
@@ -107,13 +110,14 @@ func NewNamespaceDeclNode(keyword *KeywordNode, name IdentLiteralElement,
 	}
 }
 
-// RootDeclNode represents the root type of a .fbs file. Example:
+// RootDeclNode represents the root type of a .fbs file. Examples:
 //
 // root_type Monster;
+// root_type MyGame.Example.Monster;
 type RootDeclNode struct {
 	compositeNode
 	Keyword   *KeywordNode
-	Name      *IdentNode
+	Name      IdentLiteralElement
 	Semicolon *RuneNode
 }
 
@@ -121,7 +125,7 @@ type RootDeclNode struct {
 func (*RootDeclNode) AsDeclElement() {}
 
 // NewRootDeclNode creates root declaration node.
-func NewRootDeclNode(keyword *KeywordNode, name *IdentNode, semicolon *RuneNode) *RootDeclNode {
+func NewRootDeclNode(keyword *KeywordNode, name IdentLiteralElement, semicolon *RuneNode) *RootDeclNode {
 	children := []Node{keyword, name, semicolon}
 	return &RootDeclNode{
 		compositeNode: compositeNode{children: children},
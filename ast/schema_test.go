@@ -17,7 +17,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestSchema(t *testing.T) {
@@ -41,4 +41,5 @@ func TestSchema(t *testing.T) {
 	schema := ast.NewSchemaNode(includes, decls)
 	assert.Equal(t, includes, schema.Includes)
 	assert.Equal(t, decls, schema.Decls)
+	assert.Equal(t, name, rootDecl.Name)
 }
@@ -16,7 +16,7 @@ package ast_test
 import (
 	"testing"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestSourcePos(t *testing.T) {
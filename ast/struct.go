@@ -52,7 +52,9 @@ func WithStructName(name *IdentNode) StructDeclOption {
 func WithStructMetadata(metadata *MetadataNode) StructDeclOption {
 	return func(node *StructDeclNode) {
 		node.Metadata = metadata
-		node.compositeNode.children = append(node.compositeNode.children, metadata)
+		if metadata != nil {
+			node.compositeNode.children = append(node.compositeNode.children, metadata)
+		}
 	}
 }
 
@@ -54,7 +54,9 @@ func WithTableName(name *IdentNode) TableDeclOption {
 func WithTableMetadata(metadata *MetadataNode) TableDeclOption {
 	return func(node *TableDeclNode) {
 		node.Metadata = metadata
-		node.compositeNode.children = append(node.compositeNode.children, metadata)
+		if metadata != nil {
+			node.compositeNode.children = append(node.compositeNode.children, metadata)
+		}
 	}
 }
 
@@ -152,7 +154,9 @@ func WithFieldScalar(scalar ValueNode) FieldOption {
 func WithFieldMetadata(metadata *MetadataNode) FieldOption {
 	return func(node *FieldNode) {
 		node.Metadata = metadata
-		node.compositeNode.children = append(node.compositeNode.children, metadata)
+		if metadata != nil {
+			node.compositeNode.children = append(node.compositeNode.children, metadata)
+		}
 	}
 }
 
@@ -54,7 +54,9 @@ func WithUnionName(name *IdentNode) UnionDeclOption {
 func WithUnionMetadata(metadata *MetadataNode) UnionDeclOption {
 	return func(node *UnionDeclNode) {
 		node.Metadata = metadata
-		node.compositeNode.children = append(node.compositeNode.children, metadata)
+		if metadata != nil {
+			node.compositeNode.children = append(node.compositeNode.children, metadata)
+		}
 	}
 }
 
@@ -103,6 +105,7 @@ type UnionValueNode struct {
 	Name     *IdentNode
 	Colon    *RuneNode
 	TypeName *TypeNameNode
+	Comma    *RuneNode // The comma following the value, could be nil.
 }
 
 // NewUnionValueNode creates value node for enum or union.
@@ -121,3 +124,9 @@ func NewUnionValueNode(name *IdentNode, colon *RuneNode, typeName *TypeNameNode)
 		TypeName:      typeName,
 	}
 }
+
+// AddComma sets the comma following the value.
+func (u *UnionValueNode) AddComma(comma *RuneNode) {
+	u.Comma = comma
+	u.compositeNode.children = append(u.compositeNode.children, comma)
+}
@@ -0,0 +1,164 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package ast
+
+// Visitor's Visit method is invoked for each node encountered by Walk. If the result visitor w
+// is not nil, Walk visits each of the children of node with the visitor w, followed by a call
+// of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order: It starts by calling v.Visit(node); node must
+// not be nil. If the visitor w returned by v.Visit(node) is not nil, Walk is invoked
+// recursively with visitor w for each of the children of node, followed by a call of
+// w.Visit(nil).
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	if c, ok := node.(CompositeNode); ok {
+		for _, child := range c.Children() {
+			Walk(v, child)
+		}
+	}
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+// Visit implements Visitor interface.
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling f(node); node must not
+// be nil. If f returns true, Inspect invokes f recursively for each of the children of node,
+// followed by a call of f(nil). Example:
+//
+//	ast.Inspect(schema, func(n ast.Node) bool {
+//		if f, ok := n.(*ast.FieldNode); ok {
+//			fmt.Println(f.Name.Val)
+//		}
+//		return true
+//	})
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// WalkPrePost traverses an AST in depth-first order, calling pre before the children of a
+// node are visited and post after. If pre returns false, the children of the node are skipped
+// and post is not called for the node. Either pre or post could be nil.
+func WalkPrePost(node Node, pre func(Node) bool, post func(Node)) {
+	if pre != nil && !pre(node) {
+		return
+	}
+	if c, ok := node.(CompositeNode); ok {
+		for _, child := range c.Children() {
+			WalkPrePost(child, pre, post)
+		}
+	}
+	if post != nil {
+		post(node)
+	}
+}
+
+// SimpleVisitor implements Visitor by dispatching nodes to typed callbacks. Nil callbacks are
+// skipped. If a callback returns false, the children of the node are not visited. Example:
+//
+//	ast.Walk(&ast.SimpleVisitor{
+//		VisitField: func(n *ast.FieldNode) bool {
+//			fmt.Println(n.Name.Val)
+//			return false
+//		},
+//	}, schema)
+type SimpleVisitor struct {
+	VisitSchema        func(*SchemaNode) bool
+	VisitInclude       func(*IncludeNode) bool
+	VisitNamespace     func(*NamespaceDeclNode) bool
+	VisitTable         func(*TableDeclNode) bool
+	VisitStruct        func(*StructDeclNode) bool
+	VisitField         func(*FieldNode) bool
+	VisitEnum          func(*EnumDeclNode) bool
+	VisitEnumValue     func(*EnumValueNode) bool
+	VisitUnion         func(*UnionDeclNode) bool
+	VisitUnionValue    func(*UnionValueNode) bool
+	VisitRoot          func(*RootDeclNode) bool
+	VisitFileExt       func(*FileExtDeclNode) bool
+	VisitFileIdent     func(*FileIdentDeclNode) bool
+	VisitAttr          func(*AttrDeclNode) bool
+	VisitRPC           func(*RPCDeclNode) bool
+	VisitRPCMethod     func(*RPCMethodNode) bool
+	VisitMetadata      func(*MetadataNode) bool
+	VisitMetadataEntry func(*MetadataEntryNode) bool
+	VisitTypeName      func(*TypeNameNode) bool
+	VisitTerminal      func(TerminalNode) bool
+}
+
+// Visit implements Visitor interface.
+func (v *SimpleVisitor) Visit(node Node) Visitor {
+	if node == nil || v.visit(node) {
+		return v
+	}
+	return nil
+}
+
+func (v *SimpleVisitor) visit(node Node) bool {
+	switch n := node.(type) {
+	case *SchemaNode:
+		return v.VisitSchema == nil || v.VisitSchema(n)
+	case *IncludeNode:
+		return v.VisitInclude == nil || v.VisitInclude(n)
+	case *NamespaceDeclNode:
+		return v.VisitNamespace == nil || v.VisitNamespace(n)
+	case *TableDeclNode:
+		return v.VisitTable == nil || v.VisitTable(n)
+	case *StructDeclNode:
+		return v.VisitStruct == nil || v.VisitStruct(n)
+	case *FieldNode:
+		return v.VisitField == nil || v.VisitField(n)
+	case *EnumDeclNode:
+		return v.VisitEnum == nil || v.VisitEnum(n)
+	case *EnumValueNode:
+		return v.VisitEnumValue == nil || v.VisitEnumValue(n)
+	case *UnionDeclNode:
+		return v.VisitUnion == nil || v.VisitUnion(n)
+	case *UnionValueNode:
+		return v.VisitUnionValue == nil || v.VisitUnionValue(n)
+	case *RootDeclNode:
+		return v.VisitRoot == nil || v.VisitRoot(n)
+	case *FileExtDeclNode:
+		return v.VisitFileExt == nil || v.VisitFileExt(n)
+	case *FileIdentDeclNode:
+		return v.VisitFileIdent == nil || v.VisitFileIdent(n)
+	case *AttrDeclNode:
+		return v.VisitAttr == nil || v.VisitAttr(n)
+	case *RPCDeclNode:
+		return v.VisitRPC == nil || v.VisitRPC(n)
+	case *RPCMethodNode:
+		return v.VisitRPCMethod == nil || v.VisitRPCMethod(n)
+	case *MetadataNode:
+		return v.VisitMetadata == nil || v.VisitMetadata(n)
+	case *MetadataEntryNode:
+		return v.VisitMetadataEntry == nil || v.VisitMetadataEntry(n)
+	case *TypeNameNode:
+		return v.VisitTypeName == nil || v.VisitTypeName(n)
+	case TerminalNode:
+		return v.VisitTerminal == nil || v.VisitTerminal(n)
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"trpc.group/trpc-go/fbs/ast"
+)
+
+func newWalkTestMetadata() *ast.MetadataNode {
+	openParen := ast.NewRuneNode('(', ast.Token{})
+	closeParen := ast.NewRuneNode(')', ast.Token{})
+	colon := ast.NewRuneNode(':', ast.Token{})
+	id := ast.NewMetadataEntryNode(ast.NewIdentNode("id", ast.Token{}), colon,
+		ast.NewUintLiteralNode(1, ast.Token{}))
+	key := ast.NewMetadataEntryNode(ast.NewIdentNode("key", ast.Token{}), nil, nil)
+	return ast.NewMetadataNode(openParen, []*ast.MetadataEntryNode{id, key}, closeParen)
+}
+
+func TestInspect(t *testing.T) {
+	metadata := newWalkTestMetadata()
+	var visited []ast.Node
+	ast.Inspect(metadata, func(n ast.Node) bool {
+		if n != nil {
+			visited = append(visited, n)
+		}
+		return true
+	})
+	// metadata, '(', id entry and its 3 children, key entry and its ident, ')'.
+	assert.Equal(t, 9, len(visited))
+	assert.Equal(t, metadata, visited[0])
+
+	var entries int
+	ast.Inspect(metadata, func(n ast.Node) bool {
+		if _, ok := n.(*ast.MetadataEntryNode); ok {
+			entries++
+			return false
+		}
+		return true
+	})
+	assert.Equal(t, 2, entries)
+}
+
+func TestWalkPrePost(t *testing.T) {
+	metadata := newWalkTestMetadata()
+	var pre, post []ast.Node
+	ast.WalkPrePost(metadata, func(n ast.Node) bool {
+		pre = append(pre, n)
+		_, ok := n.(*ast.MetadataEntryNode)
+		return !ok
+	}, func(n ast.Node) {
+		post = append(post, n)
+	})
+	// metadata, '(', id entry, key entry, ')'.
+	assert.Equal(t, 5, len(pre))
+	// Entries are skipped, so post is not called for them.
+	assert.Equal(t, 3, len(post))
+	assert.Equal(t, metadata, post[len(post)-1])
+
+	ast.WalkPrePost(metadata, nil, nil)
+}
+
+func TestSimpleVisitor(t *testing.T) {
+	metadata := newWalkTestMetadata()
+	var keys []string
+	var terminals int
+	ast.Walk(&ast.SimpleVisitor{
+		VisitMetadataEntry: func(n *ast.MetadataEntryNode) bool {
+			keys = append(keys, n.Key.Val)
+			return false
+		},
+		VisitTerminal: func(n ast.TerminalNode) bool {
+			terminals++
+			return true
+		},
+	}, metadata)
+	assert.Equal(t, []string{"id", "key"}, keys)
+	assert.Equal(t, 2, terminals)
+}
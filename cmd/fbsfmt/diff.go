@@ -0,0 +1,204 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around changes in a diff.
+const context = 3
+
+// edit is a line of a diff, whose kind is one of ' ', '-' and '+'.
+type edit struct {
+	kind byte
+	text string
+}
+
+// diff returns the differences between a and b in unified format.
+func diff(name string, a, b []byte) string {
+	edits := lineEdits(splitLines(a), splitLines(b))
+	// aLines[i] and bLines[i] are the numbers of lines of a and b before edits[i].
+	aLines := make([]int, len(edits)+1)
+	bLines := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if e.kind != '+' {
+			aLines[i+1]++
+		}
+		if e.kind != '-' {
+			bLines[i+1]++
+		}
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s.orig\n+++ %s\n", name, name)
+	for i := 0; i < len(edits); {
+		if edits[i].kind == ' ' {
+			i++
+			continue
+		}
+		start, end := maxInt(i-context, 0), hunkEnd(edits, i)
+		aCount, bCount := aLines[end]-aLines[start], bLines[end]-bLines[start]
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(aLines[start], aCount), hunkRange(bLines[start], bCount))
+		for _, e := range edits[start:end] {
+			buf.WriteByte(e.kind)
+			buf.WriteString(e.text)
+			if !strings.HasSuffix(e.text, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return buf.String()
+}
+
+// hunkEnd returns the end of the hunk containing the change at edits[i].
+func hunkEnd(edits []edit, i int) int {
+	for i < len(edits) {
+		if edits[i].kind != ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].kind == ' ' {
+			j++
+		}
+		if j == len(edits) || j-i > 2*context {
+			return minInt(i+context, len(edits))
+		}
+		i = j
+	}
+	return i
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// lineEdits computes the edits turning x into y. Common lines at both ends are kept as is,
+// the lines in between are compared with the algorithm of Myers, which takes O((N+M)D) time
+// and O(D²) memory for D edits.
+func lineEdits(x, y []string) []edit {
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	var edits []edit
+	for _, line := range x[:prefix] {
+		edits = append(edits, edit{' ', line})
+	}
+	edits = append(edits, myers(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, line := range x[len(x)-suffix:] {
+		edits = append(edits, edit{' ', line})
+	}
+	return edits
+}
+
+// myers computes the shortest edit script turning x into y. trace[d][k+d] is the furthest
+// line of x reached with d edits on the diagonal k, that is where the line of y is the line
+// of x minus k.
+func myers(x, y []string) []edit {
+	n, m := len(x), len(y)
+	var trace [][]int
+	for d := 0; ; d++ {
+		v := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var i int
+			switch {
+			case d == 0:
+				i = 0
+			case k == -d || k != d && trace[d-1][k-1+d-1] < trace[d-1][k+1+d-1]:
+				i = trace[d-1][k+1+d-1] // insertion of a line of y.
+			default:
+				i = trace[d-1][k-1+d-1] + 1 // deletion of a line of x.
+			}
+			for i < n && i-k < m && x[i] == y[i-k] {
+				i++
+			}
+			v[k+d] = i
+		}
+		trace = append(trace, v)
+		if k := n - m; k >= -d && k <= d && v[k+d] >= n {
+			break
+		}
+	}
+	// Walk the trace back from the end.
+	var edits []edit
+	i, j := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		k := i - j
+		prev := trace[d-1]
+		var pk int
+		if k == -d || k != d && prev[k-1+d-1] < prev[k+1+d-1] {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		// The edit leads from the end of diagonal pk to the start of the snake ending at (i, j).
+		start := prev[pk+d-1]
+		if pk == k-1 {
+			start++
+		}
+		for i > start {
+			i--
+			j--
+			edits = append(edits, edit{' ', x[i]})
+		}
+		if pk == k+1 {
+			j--
+			edits = append(edits, edit{'+', y[j]})
+		} else {
+			i--
+			edits = append(edits, edit{'-', x[i]})
+		}
+	}
+	for i > 0 {
+		i--
+		edits = append(edits, edit{' ', x[i]})
+	}
+	for l, r := 0, len(edits)-1; l < r; l, r = l+1, r-1 {
+		edits[l], edits[r] = edits[r], edits[l]
+	}
+	return edits
+}
+
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,97 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+	want := `--- x.fbs.orig
++++ x.fbs
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+\ No newline at end of file
+`
+	assert.Equal(t, want, diff("x.fbs", []byte(a), []byte(b)))
+	assert.Equal(t, "--- x.fbs.orig\n+++ x.fbs\n", diff("x.fbs", []byte(a), []byte(a)))
+}
+
+func TestLineEdits(t *testing.T) {
+	apply := func(edits []edit) (a, b []string) {
+		for _, e := range edits {
+			if e.kind != '+' {
+				a = append(a, e.text)
+			}
+			if e.kind != '-' {
+				b = append(b, e.text)
+			}
+		}
+		return a, b
+	}
+	tests := []struct {
+		x, y  string
+		edits int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abcabba", "cbabac", 5},
+		{"axbxc", "aybyc", 4},
+		{"abcdef", "xabcdefy", 2},
+	}
+	for _, tt := range tests {
+		x, y := strings.Split(tt.x, ""), strings.Split(tt.y, "")
+		edits := lineEdits(x, y)
+		a, b := apply(edits)
+		assert.Equal(t, len(x), len(a), "%q => %q", tt.x, tt.y)
+		assert.Equal(t, strings.Join(x, ""), strings.Join(a, ""))
+		assert.Equal(t, strings.Join(y, ""), strings.Join(b, ""))
+		n := 0
+		for _, e := range edits {
+			if e.kind != ' ' {
+				n++
+			}
+		}
+		assert.Equal(t, tt.edits, n, "%q => %q", tt.x, tt.y)
+	}
+	// A large file with a few changes is diffed quickly.
+	var x, y []string
+	for i := 0; i < 100000; i++ {
+		line := strconv.Itoa(i) + "\n"
+		x = append(x, line)
+		if i%10000 != 0 {
+			y = append(y, line)
+		}
+	}
+	assert.Equal(t, 10, len(lineEdits(x, y))-len(y))
+}
@@ -0,0 +1,161 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+// Fbsfmt formats .fbs files in canonical style.
+//
+// Usage:
+//
+//	fbsfmt [flags] [path ...]
+//
+// Without paths, it formats the standard input. A directory path formats all the .fbs files
+// in it recursively. By default, the formatted source is printed to the standard output.
+//
+// The flags are:
+//
+//	-d
+//		Do not print formatted source, print diffs to the standard output instead.
+//	-l
+//		Do not print formatted source, list files whose formatting differs instead.
+//	-w
+//		Do not print formatted source, write the result back to the source file instead.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"trpc.group/trpc-go/fbs/format"
+)
+
+var (
+	doDiff  = flag.Bool("d", false, "display diffs instead of rewriting files")
+	list    = flag.Bool("l", false, "list files whose formatting differs from fbsfmt's")
+	write   = flag.Bool("w", false, "write result to (source) file instead of stdout")
+	exitErr = false
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: fbsfmt [flags] [path ...]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "error: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		if err := processFile("<standard input>", os.Stdin, os.Stdout); err != nil {
+			report(err)
+		}
+		os.Exit(exitCode())
+	}
+	for _, path := range flag.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			report(err)
+			continue
+		}
+		if info.IsDir() {
+			walkDir(path)
+			continue
+		}
+		if err := processFile(path, nil, os.Stdout); err != nil {
+			report(err)
+		}
+	}
+	os.Exit(exitCode())
+}
+
+func exitCode() int {
+	if exitErr {
+		return 2
+	}
+	return 0
+}
+
+func report(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	exitErr = true
+}
+
+func walkDir(dir string) {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".fbs") {
+			return nil
+		}
+		if err := processFile(path, nil, os.Stdout); err != nil {
+			report(err)
+		}
+		return nil
+	})
+	if err != nil {
+		report(err)
+	}
+}
+
+// processFile formats the file. If in is nil, the file is read from filename.
+func processFile(filename string, in io.Reader, out io.Writer) error {
+	if in == nil {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	res, err := format.Source(filename, src)
+	if err != nil {
+		return err
+	}
+	if !*list && !*write && !*doDiff {
+		_, err := out.Write(res)
+		return err
+	}
+	if bytes.Equal(src, res) {
+		return nil
+	}
+	if *list {
+		fmt.Fprintln(out, filename)
+	}
+	if *write {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if *doDiff {
+		fmt.Fprintf(out, "diff %s fbsfmt/%s\n", filename, filename)
+		_, err := io.WriteString(out, diff(filename, src, res))
+		return err
+	}
+	return nil
+}
@@ -15,9 +15,10 @@ package fbs
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 // Desc provides an interface for all descriptors in flatbuffers.
@@ -45,9 +46,27 @@ var _ NamespaceDesc = (*EnumDesc)(nil)
 var _ NamespaceDesc = (*UnionDesc)(nil)
 var _ NamespaceDesc = (*RPCDesc)(nil)
 
+// NamedDesc provides an interface for descriptors that have fully qualified names.
+type NamedDesc interface {
+	Desc
+	// FullName returns the fully qualified name of the descriptor, which is the same as the name
+	// used by the linker to resolve references. Example: "MyGame.Example.Monster.hp".
+	FullName() string
+}
+
+var _ NamedDesc = (*TableDesc)(nil)
+var _ NamedDesc = (*StructDesc)(nil)
+var _ NamedDesc = (*FieldDesc)(nil)
+var _ NamedDesc = (*EnumDesc)(nil)
+var _ NamedDesc = (*EnumValDesc)(nil)
+var _ NamedDesc = (*UnionDesc)(nil)
+var _ NamedDesc = (*UnionValDesc)(nil)
+var _ NamedDesc = (*RPCDesc)(nil)
+var _ NamedDesc = (*MethodDesc)(nil)
+
 // TableStructDesc provides an interface for table and struct descriptors.
 type TableStructDesc interface {
-	Desc
+	NamedDesc
 	NamespaceDesc
 	GetName() string
 	GetFields() []*FieldDesc
@@ -60,8 +79,13 @@ var _ TableStructDesc = (*StructDesc)(nil)
 type SchemaDesc struct {
 	// Schema stores the root node of the AST.
 	Schema *ast.SchemaNode
-	// Name stores the .fbs file name.
+	// Name stores the .fbs file name, as given to the parser or as written in the include
+	// statement it is first included by.
 	Name string
+	// Path identifies the .fbs file: it is the absolute path of the file on the file system, or
+	// the clean path given to Parser.Accessor. A file included under different names has a
+	// single descriptor.
+	Path string
 	// Namespace of schema will change in the course of processing each decl, these will be stored
 	// in this slice. Decls of table/struct/enum/union will set their own namespaces to be the last
 	// namespace in this slice.
@@ -69,6 +93,9 @@ type SchemaDesc struct {
 	Namespaces []string
 	// Root stores root_type declaration in flatbuffers file.
 	Root string
+	// RootDesc stores the table descriptor of root_type, it is resolved by the linker and nil if
+	// there is no root_type declaration.
+	RootDesc *TableDesc
 	// FileExt stores file_extension declaration in flatbuffers file.
 	FileExt string
 	// FileIdent stores file_identifier declaration in flatbuffers file.
@@ -89,17 +116,44 @@ type SchemaDesc struct {
 	Unions []*UnionDesc
 	// RPCs stores all rpc service descriptors.
 	RPCs []*RPCDesc
+
+	// nodes maps the descriptors defined in this schema to their nodes in the AST.
+	nodes map[Desc]ast.Node
 }
 
 // FbsDesc implements Desc interface.
 func (SchemaDesc) FbsDesc() {}
 
+// SourceInfo returns the source range of a descriptor defined in this schema, including the
+// schema itself. The second return value is false if d is not defined in this schema. Example:
+//
+//	for _, f := range fd.Tables[0].Fields {
+//		if r, ok := fd.SourceInfo(f); ok {
+//			fmt.Printf("field %s at %v\n", f.Name, r.Start) // field hp at foo.fbs:12:3
+//		}
+//	}
+func (s *SchemaDesc) SourceInfo(d Desc) (ast.PosRange, bool) {
+	n, ok := s.nodes[d]
+	if !ok {
+		return ast.PosRange{}, false
+	}
+	if c, ok := n.(interface{ Children() []ast.Node }); ok && len(c.Children()) == 0 {
+		// An empty schema has no tokens at all.
+		pos := ast.Position{Filename: s.Name}
+		return ast.PosRange{Start: pos, End: pos}, true
+	}
+	return ast.PosRange{Start: *n.Start(), End: *n.End()}, true
+}
+
 // TableDesc describes the structure of table in flatbuffers.
 type TableDesc struct {
-	Schema    *SchemaDesc  // Schema stores the descriptor that contains this table.
-	Namespace string       // Namespace will be set as the current namespace of the schema node.
-	Name      string       // Name is the name of table.
-	Fields    []*FieldDesc // Fields list the fields of table.
+	Schema    *SchemaDesc   // Schema stores the descriptor that contains this table.
+	Namespace string        // Namespace will be set as the current namespace of the schema node.
+	Name      string        // Name is the name of table.
+	Fields    []*FieldDesc  // Fields list the fields of table.
+	Metadata  *MetadataDesc // Metadata stores the attributes of table, could be nil.
+	// Documentation lists the lines of doc comments (///) before the table.
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
@@ -115,6 +169,11 @@ func (t *TableDesc) GetName() string {
 	return t.Name
 }
 
+// FullName implements NamedDesc interface.
+func (t *TableDesc) FullName() string {
+	return getPrefix(t) + t.Name
+}
+
 // GetFields implements TableStructDesc interface.
 func (t *TableDesc) GetFields() []*FieldDesc {
 	return t.Fields
@@ -122,9 +181,13 @@ func (t *TableDesc) GetFields() []*FieldDesc {
 
 // StructDesc describes the structure of struct in flatbuffers.
 type StructDesc struct {
-	Namespace string       // Namespace will be set as schema's namespace.
-	Name      string       // Name is the name of struct.
-	Fields    []*FieldDesc // Fields lists the fields of the struct.
+	Schema    *SchemaDesc   // Schema stores the descriptor that contains this struct.
+	Namespace string        // Namespace will be set as schema's namespace.
+	Name      string        // Name is the name of struct.
+	Fields    []*FieldDesc  // Fields lists the fields of the struct.
+	Metadata  *MetadataDesc // Metadata stores the attributes of struct, could be nil.
+	// Documentation lists the lines of doc comments (///) before the struct.
+	Documentation []string
 }
 
 // FbsDesc implements Desc.
@@ -140,6 +203,11 @@ func (s *StructDesc) GetName() string {
 	return s.Name
 }
 
+// FullName implements NamedDesc interface.
+func (s *StructDesc) FullName() string {
+	return getPrefix(s) + s.Name
+}
+
 // GetFields implements TableStructDesc interface.
 func (s *StructDesc) GetFields() []*FieldDesc {
 	return s.Fields
@@ -147,19 +215,82 @@ func (s *StructDesc) GetFields() []*FieldDesc {
 
 // FieldDesc describes the structure of field in flatbuffers.
 type FieldDesc struct {
+	Parent   TableStructDesc // Parent stores the table or struct that contains this field.
 	Name     string
 	TypeName string
 	IsVector bool // [typename] is a vector of typename.
+	// TypeDesc stores the descriptor of the field type, or of the element type for vectors,
+	// if it is a table, struct, enum or union. It is resolved by the linker and nil for
+	// builtin types.
+	TypeDesc Desc
+	// Type describes the resolved type of the field.
+	Type FieldType
+	// Default stores the default value of the field. Its type depends on the field type:
+	// int64 for signed integers, uint64 for unsigned integers, float64 for floating points,
+	// bool for booleans and *EnumValDesc for enums. An integer default of an enum field that
+	// matches none of the enum values is kept as int64. Default is nil if no default value
+	// is given or the default value is null.
+	//
+	// Example:
+	//
+	//	hp:short = 100;        // Default: int64(100)
+	//	color:Color = Green;   // Default: *EnumValDesc of Green
+	Default interface{}
+	// DefaultIsNull reports whether the default value is explicitly set to null. Example:
+	//
+	//	maybe_i8:int8 = null;
+	DefaultIsNull bool
+	// ID is the id of the field. It is taken from the id attribute if given, otherwise it is
+	// assigned in declaration order by the linker. A union field takes two ids, its hidden
+	// type field uses ID-1.
+	ID int
+	// IsRequired reports whether the field is marked as required.
+	IsRequired bool
+	// IsDeprecated reports whether the field is marked as deprecated.
+	IsDeprecated bool
+	// IsKey reports whether the field is the key of its table or struct.
+	IsKey bool
+	// IsOptional reports whether the field is an optional scalar, i.e. its default value is null.
+	IsOptional bool
+	// Hash stores the hash algorithm given by the hash attribute, e.g. "fnv1a_64".
+	Hash string
+	// NestedFlatbuffer stores the root table of the nested flatbuffer given by the
+	// nested_flatbuffer attribute, it is resolved by the linker. Example:
+	//
+	//	testnestedflatbuffer:[ubyte] (nested_flatbuffer: "Monster");
+	NestedFlatbuffer *TableDesc
+	// Metadata stores the attributes of field, could be nil.
+	Metadata *MetadataDesc
+	// Documentation lists the lines of doc comments (///) before the field. Example:
+	//
+	//	/// the hit points.
+	//	hp:short = 100;
+	//	// Documentation: [" the hit points."]
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
 func (FieldDesc) FbsDesc() {}
 
+// FullName implements NamedDesc interface. It is Name if Parent is not set.
+func (f *FieldDesc) FullName() string {
+	if f.Parent == nil {
+		return f.Name
+	}
+	return f.Parent.FullName() + "." + f.Name
+}
+
 // EnumDesc describes the structure of enum in flatbuffers.
 type EnumDesc struct {
-	Namespace string // Namespace will be set as the current namespace of schema.
-	Name      string // Name is the name of this enum.
+	Schema    *SchemaDesc // Schema stores the descriptor that contains this enum.
+	Namespace string      // Namespace will be set as the current namespace of schema.
+	Name      string      // Name is the name of this enum.
+	TypeName  string      // TypeName is the underlying type as written, e.g. "uint8".
+	BaseType  BaseType    // BaseType is the underlying integral type of this enum.
 	Values    []*EnumValDesc
+	Metadata  *MetadataDesc // Metadata stores the attributes of enum, could be nil.
+	// Documentation lists the lines of doc comments (///) before the enum.
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
@@ -170,20 +301,53 @@ func (e *EnumDesc) GetNamespace() string {
 	return e.Namespace
 }
 
+// FullName implements NamedDesc interface.
+func (e *EnumDesc) FullName() string {
+	return getPrefix(e) + e.Name
+}
+
 // EnumValDesc describes the structure of enum value in flatbuffers.
 type EnumValDesc struct {
+	Parent *EnumDesc // Parent stores the enum that contains this value.
 	Name   string
-	Number int32
+	// Number is the value of this enum value. Values of ulong enums greater than math.MaxInt64
+	// are stored in two's complement, use Uint64 to get them back. Values of bit_flags enums
+	// are the declared bit positions. Example:
+	//
+	//	enum Color:ubyte (bit_flags) { Red = 0, Green, Blue = 3 }
+	//	// Numbers: Red 0, Green 1, Blue 3, i.e. the values are 1, 2 and 8.
+	Number   int64
+	Metadata *MetadataDesc // Metadata stores the attributes of enum value, could be nil.
+	// Documentation lists the lines of doc comments (///) before the enum value.
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
 func (EnumValDesc) FbsDesc() {}
 
+// FullName implements NamedDesc interface. Enum values are scoped under their enum. It is
+// Name if Parent is not set.
+func (e *EnumValDesc) FullName() string {
+	if e.Parent == nil {
+		return e.Name
+	}
+	return e.Parent.FullName() + "." + e.Name
+}
+
+// Uint64 returns Number as an unsigned integer, which is meaningful for unsigned enums.
+func (e *EnumValDesc) Uint64() uint64 {
+	return uint64(e.Number)
+}
+
 // UnionDesc describes the structure of union in flatbuffers.
 type UnionDesc struct {
-	Namespace string // Namespace will be set as the current namespace of schema.
+	Schema    *SchemaDesc // Schema stores the descriptor that contains this union.
+	Namespace string      // Namespace will be set as the current namespace of schema.
 	Name      string
 	Values    []*UnionValDesc
+	Metadata  *MetadataDesc // Metadata stores the attributes of union, could be nil.
+	// Documentation lists the lines of doc comments (///) before the union.
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
@@ -194,20 +358,75 @@ func (u *UnionDesc) GetNamespace() string {
 	return u.Namespace
 }
 
-// UnionValDesc describes the structure of union value in flatbuffers.
+// FullName implements NamedDesc interface.
+func (u *UnionDesc) FullName() string {
+	return getPrefix(u) + u.Name
+}
+
+// UnionNone is the name of the implicit member of every union whose discriminant is 0.
+const UnionNone = "NONE"
+
+// UnionValDesc describes the structure of union value in flatbuffers. Example:
+//
+//	union Any { Monster, M2: MyGame.Example2.Monster }
+//	                     ^^  ^^^^^^^^^^^^^^^^^^^^^^^
+//	                   Name  TypeName
 type UnionValDesc struct {
-	Name     string
-	TypeName string
+	Parent   *UnionDesc // Parent stores the union that contains this member.
+	Name     string     // Name is the alias of the member, could be empty.
+	TypeName string     // TypeName will be resolved to be fully qualified by the linker, except string.
+	// TypeDesc stores the descriptor of the member type, which is a table or a struct. It is
+	// resolved by the linker and nil for string members.
+	TypeDesc Desc
+	// Number is the discriminant of the member, starting from 1 in declaration order. The
+	// implicit NONE member has discriminant 0 and is not listed in UnionDesc.Values.
+	Number int64
+	// Documentation lists the lines of doc comments (///) before the union member.
+	Documentation []string
+
+	// member is the name of the member, which is the alias if given or the type name as
+	// written otherwise.
+	member string
 }
 
 // FbsDesc implements Desc interface.
 func (UnionValDesc) FbsDesc() {}
 
+// FullName implements NamedDesc interface. Members without alias are named after their types
+// with dots replaced by underscores as flatc does, e.g. "MyGame.Any.MyGame_Example2_Monster".
+// It is the member name alone if Parent is not set.
+func (u *UnionValDesc) FullName() string {
+	name := strings.Replace(u.MemberName(), ".", "_", -1)
+	if u.Parent == nil {
+		return name
+	}
+	return u.Parent.FullName() + "." + name
+}
+
+// MemberName returns the name of the member, which is the alias if given, or the type name as
+// written in the source otherwise. Example:
+//
+//	union Any { Monster, M2: MyGame.Example2.Monster }
+//	// MemberNames: "Monster", "M2"
+func (u *UnionValDesc) MemberName() string {
+	if u.member != "" {
+		return u.member
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	return strings.TrimPrefix(u.TypeName, ".")
+}
+
 // RPCDesc describes the structure of rpc_service in flatbuffers.
 type RPCDesc struct {
-	Namespace string // Namespace will be set as schema's namespace.
+	Schema    *SchemaDesc // Schema stores the descriptor that contains this rpc_service.
+	Namespace string      // Namespace will be set as schema's namespace.
 	Name      string
 	Methods   []*MethodDesc
+	Metadata  *MetadataDesc // Metadata stores the attributes of rpc_service, could be nil.
+	// Documentation lists the lines of doc comments (///) before the rpc_service.
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
@@ -218,8 +437,14 @@ func (r *RPCDesc) GetNamespace() string {
 	return r.Namespace
 }
 
+// FullName implements NamedDesc interface.
+func (r *RPCDesc) FullName() string {
+	return getPrefix(r) + r.Name
+}
+
 // MethodDesc describes the structure of method in flatbuffers.
 type MethodDesc struct {
+	Parent          *RPCDesc // Parent stores the rpc_service that contains this method.
 	Name            string
 	InputType       string
 	InputTypeDesc   *TableDesc
@@ -228,19 +453,77 @@ type MethodDesc struct {
 	ClientStreaming bool
 	ServerStreaming bool
 	Metadata        *MetadataDesc
+	// Documentation lists the lines of doc comments (///) before the method.
+	Documentation []string
 }
 
 // FbsDesc implements Desc interface.
 func (MethodDesc) FbsDesc() {}
 
-// MetadataDesc describes the structure of metadata in flatbuffers.
+// FullName implements NamedDesc interface. It is Name if Parent is not set.
+func (m *MethodDesc) FullName() string {
+	if m.Parent == nil {
+		return m.Name
+	}
+	return m.Parent.FullName() + "." + m.Name
+}
+
+// MetadataDesc describes the structure of metadata in flatbuffers. Example:
+//
+//	id:ulong (key, hash:"fnv1a_64");
+//	          ^^^^^^^^^^^^^^^^^^^^^
+//	KV: {"key": nil, "hash": "fnv1a_64"}
+//	Keys: ["key", "hash"]
+//
+// Values in KV are nil for attributes without value, string for string literals, bool for
+// boolean literals, uint64 for non-negative integers, int64 for negative integers and float64
+// for floating points.
 type MetadataDesc struct {
-	KV map[string]interface{}
+	KV   map[string]interface{}
+	Keys []string // Keys lists the attribute names in declaration order.
 }
 
 // FbsDesc implements Desc interface.
 func (MetadataDesc) FbsDesc() {}
 
+// Has reports whether the attribute named key is present. It is safe to call on nil.
+func (m *MetadataDesc) Has(key string) bool {
+	if m == nil {
+		return false
+	}
+	_, ok := m.KV[key]
+	return ok
+}
+
+// GetInt returns the integer value of the attribute named key. The second return value
+// is false if the attribute is absent or its value is not an integer fitting into int64.
+// It is safe to call on nil.
+func (m *MetadataDesc) GetInt(key string) (int64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	switch v := m.KV[key].(type) {
+	case int64:
+		return v, true
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// GetString returns the string value of the attribute named key. The second return value
+// is false if the attribute is absent or its value is not a string. It is safe to call on nil.
+func (m *MetadataDesc) GetString(key string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	v, ok := m.KV[key].(string)
+	return v, ok
+}
+
 // isType returns whether the given descriptor is a valid type.
 func isType(d Desc) bool {
 	switch d.(type) {
@@ -14,6 +14,7 @@
 package fbs
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -54,3 +55,57 @@ func TestDesc(t *testing.T) {
 	rpcDesc = &RPCDesc{Namespace: name}
 	assert.Equal(t, name, rpcDesc.GetNamespace())
 }
+
+func TestMetadataDesc(t *testing.T) {
+	var nilMetadata *MetadataDesc
+	assert.False(t, nilMetadata.Has("key"))
+	_, ok := nilMetadata.GetInt("id")
+	assert.False(t, ok)
+	_, ok = nilMetadata.GetString("hash")
+	assert.False(t, ok)
+	md := &MetadataDesc{KV: map[string]interface{}{
+		"key":   nil,
+		"id":    uint64(3),
+		"neg":   int64(-3),
+		"large": uint64(math.MaxUint64),
+		"hash":  "fnv1a_64",
+	}}
+	assert.True(t, md.Has("key"))
+	assert.False(t, md.Has("required"))
+	i, ok := md.GetInt("id")
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), i)
+	i, ok = md.GetInt("neg")
+	assert.True(t, ok)
+	assert.Equal(t, int64(-3), i)
+	_, ok = md.GetInt("large")
+	assert.False(t, ok)
+	_, ok = md.GetInt("hash")
+	assert.False(t, ok)
+	str, ok := md.GetString("hash")
+	assert.True(t, ok)
+	assert.Equal(t, "fnv1a_64", str)
+	_, ok = md.GetString("key")
+	assert.False(t, ok)
+}
+
+func TestUnionValMemberName(t *testing.T) {
+	union := &UnionDesc{Namespace: "a", Name: "Any"}
+	written := &UnionValDesc{Parent: union, TypeName: ".b.Monster", member: "b.Monster"}
+	assert.Equal(t, "b.Monster", written.MemberName())
+	assert.Equal(t, "a.Any.b_Monster", written.FullName())
+	alias := &UnionValDesc{Parent: union, Name: "M", TypeName: ".b.Monster"}
+	assert.Equal(t, "M", alias.MemberName())
+	byHand := &UnionValDesc{Parent: union, TypeName: ".b.Monster"}
+	assert.Equal(t, "b.Monster", byHand.MemberName())
+	assert.Equal(t, "a.Any.b_Monster", byHand.FullName())
+}
+
+func TestFullNameWithoutParent(t *testing.T) {
+	assert.Equal(t, "hp", (&FieldDesc{Name: "hp"}).FullName())
+	assert.Equal(t, "Red", (&EnumValDesc{Name: "Red"}).FullName())
+	assert.Equal(t, "b_Monster", (&UnionValDesc{TypeName: ".b.Monster"}).FullName())
+	assert.Equal(t, "Store", (&MethodDesc{Name: "Store"}).FullName())
+	table := &TableDesc{Namespace: "a", Name: "T"}
+	assert.Equal(t, "a.T.hp", (&FieldDesc{Parent: table, Name: "hp"}).FullName())
+}
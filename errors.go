@@ -14,9 +14,12 @@
 package fbs
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 // ErrorWithPos wrap error with position information in the
@@ -24,6 +27,8 @@ import (
 type ErrorWithPos struct {
 	Err error
 	Pos *ast.Position
+	// End is the position right after the source text the error is about, if known.
+	End *ast.Position
 }
 
 // Error implements the error interface.
@@ -45,9 +50,225 @@ func (e ErrorWithPos) Unwrap() error {
 	return e.Err
 }
 
+// ErrorList is the error returned when all errors are requested (see Parser.AllErrors), or
+// when Parser.Reporter lets parsing go on after errors. It holds every reported error sorted
+// by filename, line and column.
+type ErrorList []ErrorWithPos
+
+// Error implements the error interface, one error per line.
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, err := range l {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// sort orders the errors by position. Errors at the same position keep
+// the order in which they were reported.
+func (l ErrorList) sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i].GetPos(), l[j].GetPos()
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+}
+
+// SyntaxError is reported for source text that does not follow the grammar.
+type SyntaxError struct {
+	// Unexpected is the unexpected token, if known.
+	Unexpected string
+	// Expected lists the tokens expected instead, if known.
+	Expected []string
+	// Context tells where the error is, such as "after field type", if known.
+	Context string
+	// Message describes the mistake if it is a common one, such as "missing ';' after
+	// field hp". It replaces the generic message.
+	Message string
+}
+
+// newSyntaxError creates a SyntaxError out of the message of the parser, which is of the form
+// "syntax error: unexpected X, expecting A or B". Token names are made readable.
+func newSyntaxError(msg string) *SyntaxError {
+	e := &SyntaxError{}
+	msg = strings.TrimPrefix(msg, "syntax error")
+	msg = strings.TrimPrefix(msg, ": unexpected ")
+	if i := strings.Index(msg, ", expecting "); i >= 0 {
+		for _, name := range strings.Split(msg[i+len(", expecting "):], " or ") {
+			e.Expected = append(e.Expected, tokenDescription(name))
+		}
+		msg = msg[:i]
+	}
+	if msg != "" {
+		e.Unexpected = tokenDescription(msg)
+	}
+	return e
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	if e.Message != "" {
+		return "syntax error: " + e.Message
+	}
+	msg := "syntax error"
+	if e.Unexpected != "" {
+		msg += ": unexpected " + e.Unexpected
+	}
+	if n := len(e.Expected); n > 0 {
+		msg += ", expected " + strings.Join(e.Expected[:n-1], ", ")
+		if n > 1 {
+			msg += " or "
+		}
+		msg += e.Expected[n-1]
+	}
+	if e.Context != "" {
+		msg += " " + e.Context
+	}
+	return msg
+}
+
+// DuplicateSymbolError is reported for a symbol defined more than once.
+type DuplicateSymbolError struct {
+	// Name is the fully qualified name of the symbol.
+	Name string
+	// Kind is the kind of the previous definition, such as "table".
+	Kind string
+	// File is the file of the previous definition if it is not the same file.
+	File string
+	// Previous is the position of the previous definition.
+	Previous *ast.Position
+}
+
+// Error implements the error interface.
+func (e *DuplicateSymbolError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("duplicate symbol %s: already defined as %s in %q", e.Name, e.Kind, e.File)
+	}
+	return fmt.Sprintf("duplicate symbol %s: already defined as %s", e.Name, e.Kind)
+}
+
+// related implements relatedError, pointing to the previous definition.
+func (e *DuplicateSymbolError) related() []label {
+	return []label{{pos: e.Previous, msg: "previous definition of " + e.Name}}
+}
+
+// UnknownTypeError is reported for a reference to a type which is not defined.
+type UnknownTypeError struct {
+	// Scope is where the type is referred to, such as "field MyGame.Monster.pos" or "root_type".
+	Scope string
+	// Role is what the type is used as: "type", "request type", "response type" or
+	// "nested flatbuffer type".
+	Role string
+	// Name is the type name as written.
+	Name string
+	// Resolved is the fully qualified name the type name resolved to, if it is a namespace
+	// rather than a type.
+	Resolved string
+	// Suggestions lists the types which may be meant instead, the most likely first.
+	Suggestions []Suggestion
+}
+
+// Error implements the error interface.
+func (e *UnknownTypeError) Error() string {
+	msg := fmt.Sprintf("%s: unknown %s %s", e.Scope, e.Role, e.Name)
+	if e.Resolved != "" {
+		msg += fmt.Sprintf("; resolved to %s which is not defined", e.Resolved)
+	}
+	for i, s := range e.Suggestions {
+		if i == 0 {
+			msg += "; did you mean "
+		} else {
+			msg += " or "
+		}
+		msg += s.String()
+	}
+	if len(e.Suggestions) > 0 {
+		msg += "?"
+	}
+	return msg
+}
+
+// InvalidTypeError is reported for a reference to a type of a kind which is not allowed.
+type InvalidTypeError struct {
+	// Scope is where the type is referred to, such as "field MyGame.Monster.pos" or "root_type".
+	Scope string
+	// Role is what the type is used as: "type", "member type", "request type",
+	// "response type" or "nested flatbuffer type".
+	Role string
+	// Name is the fully qualified name of the type.
+	Name string
+	// Kind is the kind of the type, such as "struct".
+	Kind string
+	// Allowed lists the kinds allowed, if they are known.
+	Allowed []string
+	// Definition is the position of the definition of the type.
+	Definition *ast.Position
+}
+
+// Error implements the error interface.
+func (e *InvalidTypeError) Error() string {
+	msg := fmt.Sprintf("%s: invalid %s: %s is a %s", e.Scope, e.Role, e.Name, e.Kind)
+	switch n := len(e.Allowed); {
+	case n == 1:
+		msg += ", not a " + e.Allowed[0]
+	case n > 1:
+		msg += ", must be a " + strings.Join(e.Allowed[:n-1], ", ") + " or " + e.Allowed[n-1]
+	}
+	return msg
+}
+
+// related implements relatedError, pointing to the definition of the type.
+func (e *InvalidTypeError) related() []label {
+	return []label{{pos: e.Definition, msg: e.Name + " is defined here"}}
+}
+
+// IncludeNotFoundError is reported for a file, either given to the parser or included, which
+// can not be found in the include paths.
+type IncludeNotFoundError struct {
+	// Name is the name of the file.
+	Name string
+	// Paths lists the include paths searched.
+	Paths []string
+}
+
+// Error implements the error interface.
+func (e *IncludeNotFoundError) Error() string {
+	return fmt.Sprintf("cannot find file %v", e.Name)
+}
+
+// RangeError is reported for a value out of the range of its type.
+type RangeError struct {
+	// What is the kind of value: "value" or "bit flag".
+	What string
+	// Value is the value as written.
+	Value interface{}
+	// Min and Max are the bounds of the range, both included.
+	Min, Max interface{}
+}
+
+// Error implements the error interface.
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s %v is out of range: [%d,%d]", e.What, e.Value, e.Min, e.Max)
+}
+
 // errorHandler stores error to be handled.
 type errorHandler struct {
 	err error
+	// collect decides whether to keep going after the first error and
+	// record every error in errs.
+	collect bool
+	errs    ErrorList
+	// reporter, if set, is given every error and warning. Errors are then
+	// collected unless the reporter returns an error, which is kept in abort.
+	reporter Reporter
+	abort    error
+	// warningsAsErrors decides whether warnings are handled as errors.
+	warningsAsErrors bool
 }
 
 // newErrorHandler creates an error handler.
@@ -57,29 +278,90 @@ func newErrorHandler() *errorHandler {
 
 // handleErrorWithPos is used mostly by parser and linker to mark error position.
 func (e *errorHandler) handleErrorWithPos(pos *ast.Position, format string, args ...interface{}) error {
-	if e.err != nil {
-		return e.err
-	}
 	err := errorWithPos(pos, format, args...)
-	e.err = err
-	return err
+	return e.handle(err, err)
+}
+
+// handleErrorWithNode is used by parser and linker to report an error about the source
+// text of node.
+func (e *errorHandler) handleErrorWithNode(node ast.Node, format string, args ...interface{}) error {
+	err := errorWithPos(node.Start(), format, args...)
+	err.End = node.End()
+	return e.handle(err, err)
 }
 
 // handleError is used mostly by lexer. The passed in error err is already set with
 // position information.
 func (e *errorHandler) handleError(err error) error {
-	if e.err != nil {
+	var ewp ErrorWithPos
+	if !errors.As(err, &ewp) {
+		ewp = ErrorWithPos{Err: err}
+	}
+	return e.handle(err, ewp)
+}
+
+// handleWarning reports a warning about the source text of node to the reporter, or handles
+// it as an error if warnings are treated as errors. It returns nil for warnings.
+func (e *errorHandler) handleWarning(code WarningCode, node ast.Node, format string, args ...interface{}) error {
+	w := ErrorWithPos{Pos: node.Start(), End: node.End(), Err: &Warning{Code: code, Err: fmt.Errorf(format, args...)}}
+	if e.warningsAsErrors {
+		return e.handle(w, w)
+	}
+	if e.reporter != nil && e.abort == nil {
+		e.reporter.Warning(w)
+	}
+	return nil
+}
+
+// handle records err, whose positioned form is ewp. Only the first error is
+// kept unless errors are collected.
+func (e *errorHandler) handle(err error, ewp ErrorWithPos) error {
+	if e.abort != nil {
+		return e.abort
+	}
+	if e.err != nil && !e.collect {
 		return e.err
 	}
-	e.err = err
+	if e.err == nil {
+		e.err = err
+	}
+	if !e.collect {
+		return err
+	}
+	e.errs = append(e.errs, ewp)
+	if e.reporter != nil {
+		if abort := e.reporter.Error(ewp); abort != nil {
+			e.abort = abort
+			return abort
+		}
+	}
 	return err
 }
 
+// stop reports whether processing should stop because of err, which is
+// the case for any error unless all errors are collected.
+func (e *errorHandler) stop(err error) bool {
+	return err != nil && (!e.collect || e.abort != nil)
+}
+
 // getError returns the underlying error in error handler.
 func (e *errorHandler) getError() error {
 	return e.err
 }
 
+// result returns the error to be reported to the caller: the first error, the
+// error returned by the reporter, or the sorted list of all errors if they are collected.
+func (e *errorHandler) result() error {
+	if e.abort != nil {
+		return e.abort
+	}
+	if !e.collect || len(e.errs) == 0 {
+		return e.err
+	}
+	e.errs.sort()
+	return e.errs
+}
+
 // errorWithPos create an ErrorWithPos out of position information and customized message.
 func errorWithPos(pos *ast.Position, format string, args ...interface{}) ErrorWithPos {
 	return ErrorWithPos{Pos: pos, Err: fmt.Errorf(format, args...)}
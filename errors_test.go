@@ -14,11 +14,13 @@
 package fbs
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestErrors(t *testing.T) {
@@ -41,3 +43,147 @@ func TestErrors(t *testing.T) {
 	assert.NotNil(t, e1)
 	assert.Equal(t, err, e1)
 }
+
+func TestTypedErrors(t *testing.T) {
+	const dir = "./fbsfiles/error_test/"
+	parse := func(t *testing.T, filenames ...string) error {
+		p := NewParser()
+		_, err := p.ParseFiles(filenames...)
+		assert.NotNil(t, err)
+		return err
+	}
+	t.Run("syntax error", func(t *testing.T) {
+		var e *SyntaxError
+		assert.True(t, errors.As(parse(t, dir+"parse_test1.fbs"), &e))
+	})
+	t.Run("duplicate symbol", func(t *testing.T) {
+		var e *DuplicateSymbolError
+		err := parse(t, dir+"link_test1.fbs")
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "DuplicateTableName", e.Name)
+		assert.Equal(t, "table", e.Kind)
+		assert.Equal(t, "", e.File)
+		assert.Equal(t, dir+"link_test1.fbs:1:1", e.Previous.String())
+		assert.Equal(t, dir+"link_test1.fbs:5:1", err.(ErrorWithPos).GetPos().String())
+	})
+	t.Run("duplicate symbol in two files", func(t *testing.T) {
+		var e *DuplicateSymbolError
+		assert.True(t, errors.As(parse(t, dir+"link_test14.fbs", dir+"link_test13.fbs"), &e))
+		assert.Equal(t, dir+"link_test13.fbs", e.File)
+		assert.Equal(t, dir+"link_test13.fbs:1:1", e.Previous.String())
+	})
+	t.Run("unknown type", func(t *testing.T) {
+		var e *UnknownTypeError
+		assert.True(t, errors.As(parse(t, dir+"link_test11.fbs"), &e))
+		assert.Equal(t, UnknownTypeError{
+			Scope:    "field mynamespace.MyTable.myfield",
+			Role:     "type",
+			Name:     "mynamespace",
+			Resolved: "mynamespace",
+		}, *e)
+	})
+	t.Run("unknown type suggestions", func(t *testing.T) {
+		var e *UnknownTypeError
+		err := parse(t, dir+"link_test38.fbs", dir+"link_test39.fbs")
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, []Suggestion{{Name: "rpc.app.server.MyTable", File: dir + "link_test39.fbs"}}, e.Suggestions)
+		assert.Equal(t, dir+"link_test38.fbs:4:3: field rpc.app.client.Request.t: unknown type MyTabel; "+
+			"did you mean rpc.app.server.MyTable (defined in "+dir+"link_test39.fbs, which is not included)?", err.Error())
+
+		err = parse(t, dir+"link_test40.fbs")
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, dir+"link_test40.fbs:6:36: method rpc.app.client.Greeter.SayHello: unknown response type mytable; "+
+			"did you mean rpc.app.server.MyTable (defined in link_test39.fbs)?", err.Error())
+
+		// only the types of the kinds allowed where the type is referred to are suggested.
+		p := NewParser()
+		p.SetAllErrors(true)
+		p.SetAccessor(SourceAccessorFromMap(map[string]string{"kinds.fbs": "struct Vec1 { x:float; }\n" +
+			"table Vec2 {}\nenum Vec3 : byte { A }\ntable T { v:Vec; }\nunion U { Vec }\nroot_type Vec;\n"}))
+		_, err = p.ParseFiles("kinds.fbs")
+		var errs ErrorList
+		assert.True(t, errors.As(err, &errs))
+		var got [][]string
+		for _, e := range errs {
+			var unknown *UnknownTypeError
+			assert.True(t, errors.As(e, &unknown))
+			var names []string
+			for _, s := range unknown.Suggestions {
+				names = append(names, s.Name)
+			}
+			got = append(got, names)
+		}
+		assert.Equal(t, [][]string{{"Vec1", "Vec2", "Vec3"}, {"Vec1", "Vec2"}, {"Vec2"}}, got)
+	})
+	t.Run("invalid type", func(t *testing.T) {
+		var e *InvalidTypeError
+		assert.True(t, errors.As(parse(t, dir+"link_test17.fbs"), &e))
+		assert.Equal(t, "request type", e.Role)
+		assert.Equal(t, "InputType", e.Name)
+		assert.Equal(t, "enum", e.Kind)
+		assert.Equal(t, []string{"table"}, e.Allowed)
+		assert.Equal(t, dir+"link_test17.fbs:1:1", e.Definition.String())
+		assert.Equal(t, "method MyService.Method: invalid request type: InputType is a enum, not a table",
+			e.Error())
+
+		assert.True(t, errors.As(parse(t, dir+"link_test34.fbs"), &e))
+		assert.Equal(t, "root_type: invalid type: MyStruct is a struct, not a table", e.Error())
+	})
+	t.Run("include not found", func(t *testing.T) {
+		var e *IncludeNotFoundError
+		err := parse(t, dir+"link_test37.fbs")
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, "missing.fbs", e.Name)
+		assert.Equal(t, dir+"link_test37.fbs:1:9: cannot find file missing.fbs", err.Error())
+	})
+	t.Run("value out of range", func(t *testing.T) {
+		var e *RangeError
+		assert.True(t, errors.As(parse(t, dir+"parse_test3.fbs"), &e))
+		assert.Equal(t, int64(-32768), e.Min)
+		assert.Equal(t, int64(32767), e.Max)
+		assert.Equal(t, uint64(40000), e.Value)
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("MyTable", "MyTable"))
+	assert.Equal(t, 1, editDistance("MyTable", "MyTabel"))
+	assert.Equal(t, 1, editDistance("MyTable", "MyTables"))
+	assert.Equal(t, 2, editDistance("MyTable", "Table"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestSyntaxError(t *testing.T) {
+	e := newSyntaxError("syntax error: unexpected '}', expecting ';' or '('")
+	assert.Equal(t, "'}'", e.Unexpected)
+	assert.Equal(t, []string{"';'", "'('"}, e.Expected)
+	assert.Equal(t, "syntax error: unexpected '}', expected ';' or '('", e.Error())
+	assert.Equal(t, "syntax error", newSyntaxError("syntax error").Error())
+	e = newSyntaxError("syntax error: unexpected $end, expecting Ident or RPCService or '}'")
+	assert.Equal(t, "syntax error: unexpected end of file, expected identifier, 'rpc_service' or '}'", e.Error())
+}
+
+func TestSyntaxErrorMessages(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"table A { a:int }", "t.fbs:1:16: syntax error: missing ';' after field a"},
+		{"table A { a:int\n  b:int; }", "t.fbs:1:16: syntax error: missing ';' after field a"},
+		{"table A { a:int = 1 (id: 0) }", "t.fbs:1:28: syntax error: missing ';' after field a"},
+		{"table A { a:int, b:int; }", "t.fbs:1:16: syntax error: field a must end with ';', not ','"},
+		{"enum Color { Red }", "t.fbs:1:12: syntax error: enum Color: missing underlying type, such as enum Color : byte"},
+		{"enum Color:byte { Red; }", "t.fbs:1:22: syntax error: values of enum Color must be separated by ',', not ';'"},
+		{"table A { a: }", "t.fbs:1:14: syntax error: unexpected '}', expected field type"},
+		{"table A { a:[int }", "t.fbs:1:18: syntax error: unexpected '}', expected ']' after field type"},
+		{"table A { table:int; }", "t.fbs:1:11: syntax error: unexpected 'table', expected identifier or '}'"},
+		{"table A { a:int; ", "t.fbs:1:17: syntax error: unexpected end of file, expected identifier or '}'"},
+		{"namespace a b;", "t.fbs:1:13: syntax error: unexpected identifier b, expected ';'"},
+	}
+	for _, tt := range tests {
+		_, err := ParseSchema("t.fbs", strings.NewReader(tt.src))
+		assert.Equal(t, tt.want, err.Error(), tt.src)
+		var e *SyntaxError
+		assert.True(t, errors.As(err, &e))
+	}
+}
@@ -33,7 +33,7 @@
 %{
 package fbs
 
-import "trpc.group/trpc-go/fbs/internal/ast"
+import "trpc.group/trpc-go/fbs/ast"
 
 %}
 
@@ -124,9 +124,9 @@ import "trpc.group/trpc-go/fbs/internal/ast"
 %type <rpcMethod> rpcMethod
 %type <rpcMethods> rpcMethods
 %type <enumVal> enumVal
-%type <enumVals> enumVals
+%type <enumVals> enumVals enumValList
 %type <unionVal> unionVal
-%type <unionVals> unionVals
+%type <unionVals> unionVals unionValList
 
 // terminals. %token associates union member names with terminals. 
 %token <s> StrLit        // string literal
@@ -244,10 +244,10 @@ namespaceDecl: Namespace idents ';' {
 
 // idents is of type *ast.IdentList
 idents: Ident {
-		$$ = &ast.IdentList{$1, nil, nil}
+		$$ = &ast.IdentList{Ident: $1}
 	}
 	| Ident '.' idents {
-		$$ = &ast.IdentList{$1, $2, $3}
+		$$ = &ast.IdentList{Ident: $1, Dot: $2, Next: $3}
 	}
 
 // attrDecl is of type *ast.AttrDeclNode
@@ -307,8 +307,8 @@ unionDecl: Union Ident metadata '{' unionVals '}' {
 	}
 
 // rootDecl is of type *ast.RootDeclNode
-rootDecl: RootType Ident ';' {
-		$$ = ast.NewRootDeclNode($1.ToKeyword(), $2, $3)
+rootDecl: RootType idents ';' {
+		$$ = ast.NewRootDeclNode($1.ToKeyword(), $2.ToIdentValueNode(nil), $3)
 	}
 
 // fileExtDecl is of type *ast.FileExtDeclNode
@@ -322,8 +322,8 @@ fileIdentDecl: FileIdentifier StrLit ';' {
 	}
 
 // rpcDecl is of type *ast.RPCDeclNode
-rpcDecl: RPCService Ident '{' rpcMethods '}' {
-		$$ = ast.NewRPCDeclNode($1.ToKeyword(), $2, $3, $4, $5)
+rpcDecl: RPCService Ident metadata '{' rpcMethods '}' {
+		$$ = ast.NewRPCDeclNode($1.ToKeyword(), $2, $3, $4, $5, $6)
 	}
 
 // rpcMethods is of type []*ast.RPCMethodNode
@@ -349,43 +349,55 @@ rpcMethod: Ident '(' idents ')' ':' idents metadata ';' {
 	}
 
 // enumVals is of type []*ast.EnumValueNode
-enumVals: enumVal {
-		$$ = []*ast.EnumValueNode{$1}
+enumVals: enumValList {
+		$$ = $1
 	}
-	| enumVal ',' enumVals { // so the last item can have trailing ','
-		if $3 != nil {
-			$$ = append($3, $1)
-		} else {
-			$$ = []*ast.EnumValueNode{$1}
-		}
+	| enumValList ',' { // so the last item can have trailing ','
+		$1[len($1)-1].AddComma($2)
+		$$ = $1
 	}
 	| {
 		$$ = nil 
 	}
 
+// enumValList is of type []*ast.EnumValueNode
+enumValList: enumVal {
+		$$ = []*ast.EnumValueNode{$1}
+	}
+	| enumValList ',' enumVal {
+		$1[len($1)-1].AddComma($2)
+		$$ = append($1, $3)
+	}
+
 // enumVal is of type *ast.EnumValueNode
-enumVal: Ident {
-		$$ = ast.NewEnumValueNode($1, nil, nil)
+enumVal: Ident metadata {
+		$$ = ast.NewEnumValueNode($1, nil, nil, $2)
 	}
-	| Ident '=' intLit {
-		$$ = ast.NewEnumValueNode($1, $2, $3)
+	| Ident '=' intLit metadata {
+		$$ = ast.NewEnumValueNode($1, $2, $3, $4)
 	}
 
 // unionVals is of type []*ast.UnionValueNode
-unionVals: unionVal {
-		$$ = []*ast.UnionValueNode{$1}
+unionVals: unionValList {
+		$$ = $1
 	}
-	| unionVal ',' unionVals { // so the last item can have trailing ','
-		if $3 != nil {
-			$$ = append($3, $1)
-		} else {
-			$$ = []*ast.UnionValueNode{$1}
-		}
+	| unionValList ',' { // so the last item can have trailing ','
+		$1[len($1)-1].AddComma($2)
+		$$ = $1
 	}
 	| {
 		$$ = nil 
 	}
 
+// unionValList is of type []*ast.UnionValueNode
+unionValList: unionVal {
+		$$ = []*ast.UnionValueNode{$1}
+	}
+	| unionValList ',' unionVal {
+		$1[len($1)-1].AddComma($2)
+		$$ = append($1, $3)
+	}
+
 // unionVal is of type *ast.UnionValueNode
 unionVal: typeName {
 		$$ = ast.NewUnionValueNode(nil, nil, $1)
@@ -451,6 +463,9 @@ metadataEntries: metadataEntry {
 		$$ = []*ast.MetadataEntryNode{$1}
 	}
 	| metadataEntries ',' metadataEntry {
+		if len($1) > 0 {
+			$1[len($1)-1].AddComma($2)
+		}
 		$$ = append($1, $3)
 	}
 	| {
@@ -21,7 +21,7 @@ import __yyfmt__ "fmt"
 
 //line fbs.y:35
 
-import "trpc.group/trpc-go/fbs/internal/ast"
+import "trpc.group/trpc-go/fbs/ast"
 
 //line fbs.y:41
 type fbsSymType struct {
@@ -197,10 +197,10 @@ const fbsEofCode = 1
 const fbsErrCode = 2
 const fbsInitialStackSize = 16
 
-//line fbs.y:592
+//line fbs.y:607
 
 //line yacctab:1
-var fbsExca = [...]int{
+var fbsExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
@@ -214,133 +214,139 @@ var fbsExca = [...]int{
 
 const fbsPrivate = 57344
 
-const fbsLast = 251
-
-var fbsAct = [...]int{
-	46, 65, 143, 114, 104, 117, 102, 94, 59, 91,
-	129, 97, 63, 148, 121, 47, 150, 96, 93, 108,
-	152, 32, 131, 45, 151, 121, 124, 157, 119, 120,
-	47, 132, 130, 128, 89, 61, 48, 45, 50, 95,
-	33, 125, 95, 92, 67, 68, 77, 56, 57, 126,
-	74, 86, 87, 55, 62, 72, 80, 82, 84, 78,
-	75, 154, 111, 100, 98, 70, 88, 160, 161, 69,
-	71, 73, 81, 83, 85, 79, 76, 101, 122, 123,
-	99, 168, 127, 4, 167, 109, 106, 115, 121, 124,
-	49, 119, 120, 166, 155, 64, 54, 53, 52, 51,
-	107, 110, 44, 43, 125, 31, 112, 110, 153, 33,
-	133, 145, 126, 60, 105, 95, 134, 92, 67, 68,
-	77, 42, 38, 37, 74, 86, 87, 36, 35, 72,
-	80, 82, 84, 78, 75, 149, 34, 147, 146, 70,
-	88, 122, 123, 69, 71, 73, 81, 83, 85, 79,
-	76, 139, 135, 41, 156, 158, 162, 163, 164, 159,
-	33, 7, 40, 165, 67, 68, 77, 30, 39, 64,
-	74, 86, 87, 29, 103, 72, 80, 82, 84, 78,
-	75, 3, 139, 140, 6, 70, 88, 144, 90, 69,
-	71, 73, 81, 83, 85, 79, 76, 18, 141, 66,
-	118, 116, 113, 135, 136, 27, 142, 58, 18, 22,
-	25, 26, 17, 16, 15, 4, 27, 14, 13, 137,
-	22, 25, 26, 19, 12, 24, 28, 138, 11, 21,
-	20, 10, 9, 8, 19, 5, 24, 28, 2, 23,
-	21, 20, 1, 0, 0, 0, 0, 0, 0, 0,
-	23,
+const fbsLast = 288
+
+var fbsAct = [...]uint8{
+	46, 116, 65, 113, 146, 106, 103, 92, 102, 128,
+	59, 95, 157, 47, 156, 63, 152, 94, 134, 154,
+	131, 91, 32, 153, 130, 45, 45, 38, 47, 129,
+	47, 120, 127, 90, 89, 33, 48, 61, 50, 67,
+	68, 77, 57, 55, 107, 74, 86, 87, 56, 93,
+	72, 80, 82, 84, 78, 75, 62, 93, 165, 110,
+	70, 88, 96, 98, 69, 71, 73, 81, 83, 85,
+	79, 76, 120, 123, 160, 118, 119, 114, 120, 123,
+	99, 118, 119, 97, 163, 164, 49, 132, 124, 33,
+	64, 173, 126, 171, 124, 170, 125, 4, 158, 109,
+	108, 18, 125, 54, 53, 109, 111, 52, 51, 27,
+	44, 133, 43, 22, 25, 26, 31, 135, 147, 4,
+	60, 107, 93, 42, 37, 121, 122, 19, 36, 24,
+	28, 121, 122, 21, 20, 35, 151, 150, 149, 148,
+	34, 140, 141, 23, 140, 136, 7, 41, 155, 101,
+	136, 137, 30, 40, 39, 29, 159, 142, 162, 161,
+	166, 167, 100, 168, 145, 143, 138, 104, 169, 144,
+	172, 67, 68, 77, 139, 105, 66, 74, 86, 87,
+	117, 115, 72, 80, 82, 84, 78, 75, 3, 112,
+	58, 6, 70, 88, 17, 16, 69, 71, 73, 81,
+	83, 85, 79, 76, 15, 14, 13, 12, 33, 11,
+	10, 9, 67, 68, 77, 8, 5, 2, 74, 86,
+	87, 1, 64, 72, 80, 82, 84, 78, 75, 0,
+	0, 0, 0, 70, 88, 0, 0, 69, 71, 73,
+	81, 83, 85, 79, 76, 18, 0, 0, 0, 0,
+	0, 0, 0, 27, 0, 0, 0, 22, 25, 26,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 19, 0, 24, 28, 0, 0, 21, 20, 0,
+	0, 0, 0, 0, 0, 0, 0, 23,
 }
 
-var fbsPact = [...]int{
-	63, -1000, 195, -1000, 169, 206, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 58, 102,
-	129, 121, 120, 116, 115, 164, 158, 149, 114, 56,
-	-1000, -1000, 55, -31, -45, -45, 42, -45, 52, 51,
-	50, 49, 4, -1000, -1000, 102, -1, 106, -14, 33,
-	-15, -1000, -1000, -1000, -1000, 110, -1000, 108, -44, -1000,
-	16, 108, -45, -1000, 153, -1000, -1000, -1000, -1000, -1000,
+var fbsPact = [...]int16{
+	77, -1000, 99, -1000, 151, 243, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 69, 82,
+	133, 128, 121, 117, 82, 150, 149, 143, 116, 65,
+	-1000, -1000, 63, -29, -47, -47, 38, -47, 61, 60,
+	57, 56, -47, -1000, -1000, 82, -7, 113, -12, 28,
+	-15, -1000, -1000, -1000, -1000, -16, -1000, 115, -44, -1000,
+	14, 115, -47, -1000, 201, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 107,
-	36, -1000, -41, 35, -1000, 14, -1000, 106, 83, 32,
-	-16, -53, -18, -33, -1000, -17, -1000, -1000, 102, -1000,
-	-1000, 33, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, 198, 177, -1000, -1000, -1000, -1000, 104, -1000,
-	-1000, 107, 33, -48, -30, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -26, -35, 62, -1000, -1000, 13, 47,
-	20, -1000, 104, 9, 102, -1000, -45, -45, -1000, -1000,
-	147, 146, -45, 46, 37, 34, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 160,
+	114, 50, -1000, 11, -1000, 113, 73, 42, -17, -54,
+	-21, -31, -1000, -1000, -28, 37, -1000, -42, -1000, -1000,
+	28, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 145, 136, -1000, -1000, -1000, -1000, 111, -1000, -1000,
+	160, 28, -1000, -1000, 82, -30, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -27, -36, -1000, -32, -1000, -1000,
+	-49, 51, 67, -1000, 111, -1000, 26, 10, -1000, -47,
+	-47, -1000, -47, 140, 139, 82, 48, 46, -1000, -47,
+	-1000, -1000, 44, -1000,
 }
 
-var fbsPgo = [...]int{
-	0, 242, 181, 238, 161, 235, 233, 232, 231, 228,
-	224, 218, 217, 214, 213, 212, 1, 4, 0, 7,
-	18, 8, 207, 202, 3, 201, 5, 200, 12, 199,
-	9, 188, 187, 2, 174, 6,
+var fbsPgo = [...]uint8{
+	0, 221, 188, 217, 146, 216, 215, 211, 210, 209,
+	207, 206, 205, 204, 195, 194, 2, 6, 0, 7,
+	21, 10, 190, 189, 3, 181, 1, 180, 15, 176,
+	5, 175, 4, 169, 164, 8, 162, 149,
 }
 
-var fbsR1 = [...]int{
+var fbsR1 = [...]int8{
 	0, 1, 3, 3, 3, 2, 5, 5, 5, 4,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
 	4, 6, 16, 16, 14, 7, 8, 9, 10, 11,
-	12, 13, 15, 31, 31, 30, 33, 33, 33, 32,
-	32, 35, 35, 35, 34, 34, 20, 20, 20, 19,
-	19, 19, 18, 18, 22, 22, 22, 21, 21, 23,
-	23, 24, 24, 24, 25, 25, 26, 26, 26, 27,
-	27, 27, 27, 27, 27, 27, 27, 27, 17, 17,
-	28, 28, 29, 29, 29, 29, 29, 29, 29, 29,
+	12, 13, 15, 31, 31, 30, 33, 33, 33, 34,
+	34, 32, 32, 36, 36, 36, 37, 37, 35, 35,
+	20, 20, 20, 19, 19, 19, 18, 18, 22, 22,
+	22, 21, 21, 23, 23, 24, 24, 24, 25, 25,
+	26, 26, 26, 27, 27, 27, 27, 27, 27, 27,
+	27, 27, 17, 17, 28, 28, 29, 29, 29, 29,
 	29, 29, 29, 29, 29, 29, 29, 29, 29, 29,
-	29, 29, 29, 29,
+	29, 29, 29, 29, 29, 29, 29, 29,
 }
 
-var fbsR2 = [...]int{
+var fbsR2 = [...]int8{
 	0, 2, 2, 1, 0, 3, 2, 1, 0, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 2,
 	1, 3, 1, 3, 3, 6, 6, 8, 6, 3,
-	3, 3, 5, 1, 2, 8, 1, 3, 0, 1,
-	3, 1, 3, 0, 1, 3, 1, 2, 0, 5,
-	7, 7, 3, 0, 1, 3, 0, 1, 3, 1,
-	1, 1, 1, 1, 1, 1, 1, 2, 2, 1,
-	2, 2, 1, 2, 2, 1, 2, 2, 1, 3,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	3, 3, 6, 1, 2, 8, 1, 2, 0, 1,
+	3, 2, 4, 1, 2, 0, 1, 3, 1, 3,
+	1, 2, 0, 5, 7, 7, 3, 0, 1, 3,
+	0, 1, 3, 1, 1, 1, 1, 1, 1, 1,
+	1, 2, 2, 1, 2, 2, 1, 2, 2, 1,
+	2, 2, 1, 3, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1,
 }
 
-var fbsChk = [...]int{
+var fbsChk = [...]int16{
 	-1000, -1, -3, -2, 20, -5, -2, -4, -6, -7,
 	-8, -9, -10, -11, -12, -13, -14, -15, 2, 28,
 	35, 34, 14, 44, 30, 15, 16, 10, 31, 4,
-	-4, 47, -16, 7, 7, 7, 7, 7, 7, 4,
+	-4, 47, -16, 7, 7, 7, 7, 7, -16, 4,
 	4, 4, 7, 47, 47, 54, -18, 60, -18, 48,
-	-18, 47, 47, 47, 47, 49, -16, 49, -22, -21,
+	-18, 47, 47, 47, 47, -18, -16, 49, -22, -21,
 	7, 49, -17, -28, 62, -16, -29, 11, 12, 36,
 	32, 37, 22, 38, 17, 27, 43, 13, 26, 42,
 	23, 39, 24, 40, 25, 41, 18, 19, 33, 49,
-	-31, -30, 7, -20, -19, 7, 61, 55, 48, -20,
-	-18, -28, -35, -34, -17, 7, 50, -30, 60, 50,
-	-19, 48, -21, -23, -24, 4, -25, -26, -27, 8,
-	9, 5, 58, 59, 6, 21, 29, 50, 49, 63,
-	50, 55, 48, -16, -17, 5, 6, 21, 29, 5,
-	6, 21, 29, -33, -32, 7, -35, -17, 61, -18,
-	46, 50, 55, 46, 48, 47, -24, 7, -33, -26,
-	58, 59, -16, -18, -18, -18, 47, 47, 47,
+	49, -20, -19, 7, 61, 55, 48, -20, -18, -28,
+	-36, -37, -35, -17, 7, -31, -30, 7, 50, -19,
+	48, -21, -23, -24, 4, -25, -26, -27, 8, 9,
+	5, 58, 59, 6, 21, 29, 50, 49, 63, 50,
+	55, 48, 50, -30, 60, -17, 5, 6, 21, 29,
+	5, 6, 21, 29, -33, -34, -32, 7, -35, -17,
+	-16, -18, 46, 50, 55, -18, 46, 61, 47, -24,
+	7, -32, -26, 58, 59, 48, -18, -18, -18, -16,
+	47, 47, -18, 47,
 }
 
-var fbsDef = [...]int{
+var fbsDef = [...]int8{
 	4, -2, -2, 3, 0, -2, 2, 7, 9, 10,
 	11, 12, 13, 14, 15, 16, 17, 18, 20, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	6, 19, 0, 22, 53, 53, 0, 53, 0, 0,
-	0, 0, 0, 5, 21, 0, 0, 56, 0, 0,
-	0, 29, 30, 31, 24, 0, 23, 48, 0, 54,
-	57, 48, 53, 78, 0, 80, 81, 82, 83, 84,
-	85, 86, 87, 88, 89, 90, 91, 92, 93, 94,
-	95, 96, 97, 98, 99, 100, 101, 102, 103, 43,
-	0, 33, 0, 0, 46, 0, 52, 0, 0, 0,
-	0, 0, 0, 41, 44, 22, 32, 34, 0, 25,
-	47, 0, 55, 58, 59, 60, 61, 62, 63, 64,
-	65, 66, 0, 0, 69, 72, 75, 26, 38, 79,
-	28, 43, 0, 0, 53, 67, 71, 73, 76, 68,
-	70, 74, 77, 0, 36, 39, 42, 45, 0, 0,
-	0, 27, 38, 0, 0, 49, 53, 53, 37, 40,
-	0, 0, 53, 0, 0, 0, 50, 51, 35,
+	6, 19, 0, 22, 57, 57, 0, 57, 0, 0,
+	0, 0, 57, 5, 21, 0, 0, 60, 0, 0,
+	0, 29, 30, 31, 24, 0, 23, 52, 0, 58,
+	61, 52, 57, 82, 0, 84, 85, 86, 87, 88,
+	89, 90, 91, 92, 93, 94, 95, 96, 97, 98,
+	99, 100, 101, 102, 103, 104, 105, 106, 107, 45,
+	0, 0, 50, 0, 56, 0, 0, 0, 0, 0,
+	0, 43, 46, 48, 22, 0, 33, 0, 25, 51,
+	0, 59, 62, 63, 64, 65, 66, 67, 68, 69,
+	70, 0, 0, 73, 76, 79, 26, 38, 83, 28,
+	44, 0, 32, 34, 0, 57, 71, 75, 77, 80,
+	72, 74, 78, 81, 0, 36, 39, 57, 47, 49,
+	0, 0, 0, 27, 37, 41, 0, 0, 53, 57,
+	57, 40, 57, 0, 0, 0, 0, 0, 42, 57,
+	54, 55, 0, 35,
 }
 
-var fbsTok1 = [...]int{
+var fbsTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
@@ -356,7 +362,7 @@ var fbsTok1 = [...]int{
 	3, 3, 3, 49, 3, 50, 72,
 }
 
-var fbsTok2 = [...]int{
+var fbsTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -364,7 +370,7 @@ var fbsTok2 = [...]int{
 	42, 43, 44, 45,
 }
 
-var fbsTok3 = [...]int{
+var fbsTok3 = [...]int8{
 	0,
 }
 
@@ -446,9 +452,9 @@ func fbsErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := fbsPact[state]
+	base := int(fbsPact[state])
 	for tok := TOKSTART; tok-1 < len(fbsToknames); tok++ {
-		if n := base + tok; n >= 0 && n < fbsLast && fbsChk[fbsAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < fbsLast && int(fbsChk[int(fbsAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -458,13 +464,13 @@ func fbsErrorMessage(state, lookAhead int) string {
 
 	if fbsDef[state] == -2 {
 		i := 0
-		for fbsExca[i] != -1 || fbsExca[i+1] != state {
+		for fbsExca[i] != -1 || int(fbsExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; fbsExca[i] >= 0; i += 2 {
-			tok := fbsExca[i]
+			tok := int(fbsExca[i])
 			if tok < TOKSTART || fbsExca[i+1] == 0 {
 				continue
 			}
@@ -495,30 +501,30 @@ func fbslex1(lex fbsLexer, lval *fbsSymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = fbsTok1[0]
+		token = int(fbsTok1[0])
 		goto out
 	}
 	if char < len(fbsTok1) {
-		token = fbsTok1[char]
+		token = int(fbsTok1[char])
 		goto out
 	}
 	if char >= fbsPrivate {
 		if char < fbsPrivate+len(fbsTok2) {
-			token = fbsTok2[char-fbsPrivate]
+			token = int(fbsTok2[char-fbsPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(fbsTok3); i += 2 {
-		token = fbsTok3[i+0]
+		token = int(fbsTok3[i+0])
 		if token == char {
-			token = fbsTok3[i+1]
+			token = int(fbsTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = fbsTok2[1] /* unknown char */
+		token = int(fbsTok2[1]) /* unknown char */
 	}
 	if fbsDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", fbsTokname(token), uint(char))
@@ -573,7 +579,7 @@ fbsstack:
 	fbsS[fbsp].yys = fbsstate
 
 fbsnewstate:
-	fbsn = fbsPact[fbsstate]
+	fbsn = int(fbsPact[fbsstate])
 	if fbsn <= fbsFlag {
 		goto fbsdefault /* simple state */
 	}
@@ -584,8 +590,8 @@ fbsnewstate:
 	if fbsn < 0 || fbsn >= fbsLast {
 		goto fbsdefault
 	}
-	fbsn = fbsAct[fbsn]
-	if fbsChk[fbsn] == fbstoken { /* valid shift */
+	fbsn = int(fbsAct[fbsn])
+	if int(fbsChk[fbsn]) == fbstoken { /* valid shift */
 		fbsrcvr.char = -1
 		fbstoken = -1
 		fbsVAL = fbsrcvr.lval
@@ -598,7 +604,7 @@ fbsnewstate:
 
 fbsdefault:
 	/* default state action */
-	fbsn = fbsDef[fbsstate]
+	fbsn = int(fbsDef[fbsstate])
 	if fbsn == -2 {
 		if fbsrcvr.char < 0 {
 			fbsrcvr.char, fbstoken = fbslex1(fbslex, &fbsrcvr.lval)
@@ -607,18 +613,18 @@ fbsdefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if fbsExca[xi+0] == -1 && fbsExca[xi+1] == fbsstate {
+			if fbsExca[xi+0] == -1 && int(fbsExca[xi+1]) == fbsstate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			fbsn = fbsExca[xi+0]
+			fbsn = int(fbsExca[xi+0])
 			if fbsn < 0 || fbsn == fbstoken {
 				break
 			}
 		}
-		fbsn = fbsExca[xi+1]
+		fbsn = int(fbsExca[xi+1])
 		if fbsn < 0 {
 			goto ret0
 		}
@@ -640,10 +646,10 @@ fbsdefault:
 
 			/* find a state where "error" is a legal shift action */
 			for fbsp >= 0 {
-				fbsn = fbsPact[fbsS[fbsp].yys] + fbsErrCode
+				fbsn = int(fbsPact[fbsS[fbsp].yys]) + fbsErrCode
 				if fbsn >= 0 && fbsn < fbsLast {
-					fbsstate = fbsAct[fbsn] /* simulate a shift of "error" */
-					if fbsChk[fbsstate] == fbsErrCode {
+					fbsstate = int(fbsAct[fbsn]) /* simulate a shift of "error" */
+					if int(fbsChk[fbsstate]) == fbsErrCode {
 						goto fbsstack
 					}
 				}
@@ -679,7 +685,7 @@ fbsdefault:
 	fbspt := fbsp
 	_ = fbspt // guard against "declared and not used"
 
-	fbsp -= fbsR2[fbsn]
+	fbsp -= int(fbsR2[fbsn])
 	// fbsp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if fbsp+1 >= len(fbsS) {
@@ -690,16 +696,16 @@ fbsdefault:
 	fbsVAL = fbsS[fbsp+1]
 
 	/* consult goto table to find next state */
-	fbsn = fbsR1[fbsn]
-	fbsg := fbsPgo[fbsn]
+	fbsn = int(fbsR1[fbsn])
+	fbsg := int(fbsPgo[fbsn])
 	fbsj := fbsg + fbsS[fbsp].yys + 1
 
 	if fbsj >= fbsLast {
-		fbsstate = fbsAct[fbsg]
+		fbsstate = int(fbsAct[fbsg])
 	} else {
-		fbsstate = fbsAct[fbsj]
-		if fbsChk[fbsstate] != -fbsn {
-			fbsstate = fbsAct[fbsg]
+		fbsstate = int(fbsAct[fbsj])
+		if int(fbsChk[fbsstate]) != -fbsn {
+			fbsstate = int(fbsAct[fbsg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -852,13 +858,13 @@ fbsdefault:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
 //line fbs.y:246
 		{
-			fbsVAL.idents = &ast.IdentList{fbsDollar[1].id, nil, nil}
+			fbsVAL.idents = &ast.IdentList{Ident: fbsDollar[1].id}
 		}
 	case 23:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
 //line fbs.y:249
 		{
-			fbsVAL.idents = &ast.IdentList{fbsDollar[1].id, fbsDollar[2].r, fbsDollar[3].idents}
+			fbsVAL.idents = &ast.IdentList{Ident: fbsDollar[1].id, Dot: fbsDollar[2].r, Next: fbsDollar[3].idents}
 		}
 	case 24:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
@@ -924,7 +930,7 @@ fbsdefault:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
 //line fbs.y:310
 		{
-			fbsVAL.rootDecl = ast.NewRootDeclNode(fbsDollar[1].id.ToKeyword(), fbsDollar[2].id, fbsDollar[3].r)
+			fbsVAL.rootDecl = ast.NewRootDeclNode(fbsDollar[1].id.ToKeyword(), fbsDollar[2].idents.ToIdentValueNode(nil), fbsDollar[3].r)
 		}
 	case 30:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
@@ -939,10 +945,10 @@ fbsdefault:
 			fbsVAL.fileIdentDecl = ast.NewFileIdentDeclNode(fbsDollar[1].id.ToKeyword(), fbsDollar[2].s, fbsDollar[3].r)
 		}
 	case 32:
-		fbsDollar = fbsS[fbspt-5 : fbspt+1]
+		fbsDollar = fbsS[fbspt-6 : fbspt+1]
 //line fbs.y:325
 		{
-			fbsVAL.rpcDecl = ast.NewRPCDeclNode(fbsDollar[1].id.ToKeyword(), fbsDollar[2].id, fbsDollar[3].r, fbsDollar[4].rpcMethods, fbsDollar[5].r)
+			fbsVAL.rpcDecl = ast.NewRPCDeclNode(fbsDollar[1].id.ToKeyword(), fbsDollar[2].id, fbsDollar[3].metadata, fbsDollar[4].r, fbsDollar[5].rpcMethods, fbsDollar[6].r)
 		}
 	case 33:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
@@ -975,91 +981,111 @@ fbsdefault:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
 //line fbs.y:352
 		{
-			fbsVAL.enumVals = []*ast.EnumValueNode{fbsDollar[1].enumVal}
+			fbsVAL.enumVals = fbsDollar[1].enumVals
 		}
 	case 37:
-		fbsDollar = fbsS[fbspt-3 : fbspt+1]
+		fbsDollar = fbsS[fbspt-2 : fbspt+1]
 //line fbs.y:355
 		{ // so the last item can have trailing ','
-			if fbsDollar[3].enumVals != nil {
-				fbsVAL.enumVals = append(fbsDollar[3].enumVals, fbsDollar[1].enumVal)
-			} else {
-				fbsVAL.enumVals = []*ast.EnumValueNode{fbsDollar[1].enumVal}
-			}
+			fbsDollar[1].enumVals[len(fbsDollar[1].enumVals)-1].AddComma(fbsDollar[2].r)
+			fbsVAL.enumVals = fbsDollar[1].enumVals
 		}
 	case 38:
 		fbsDollar = fbsS[fbspt-0 : fbspt+1]
-//line fbs.y:362
+//line fbs.y:359
 		{
 			fbsVAL.enumVals = nil
 		}
 	case 39:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:367
+//line fbs.y:364
 		{
-			fbsVAL.enumVal = ast.NewEnumValueNode(fbsDollar[1].id, nil, nil)
+			fbsVAL.enumVals = []*ast.EnumValueNode{fbsDollar[1].enumVal}
 		}
 	case 40:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:370
+//line fbs.y:367
 		{
-			fbsVAL.enumVal = ast.NewEnumValueNode(fbsDollar[1].id, fbsDollar[2].r, fbsDollar[3].iv)
+			fbsDollar[1].enumVals[len(fbsDollar[1].enumVals)-1].AddComma(fbsDollar[2].r)
+			fbsVAL.enumVals = append(fbsDollar[1].enumVals, fbsDollar[3].enumVal)
 		}
 	case 41:
-		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:375
+		fbsDollar = fbsS[fbspt-2 : fbspt+1]
+//line fbs.y:373
 		{
-			fbsVAL.unionVals = []*ast.UnionValueNode{fbsDollar[1].unionVal}
+			fbsVAL.enumVal = ast.NewEnumValueNode(fbsDollar[1].id, nil, nil, fbsDollar[2].metadata)
 		}
 	case 42:
-		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:378
-		{ // so the last item can have trailing ','
-			if fbsDollar[3].unionVals != nil {
-				fbsVAL.unionVals = append(fbsDollar[3].unionVals, fbsDollar[1].unionVal)
-			} else {
-				fbsVAL.unionVals = []*ast.UnionValueNode{fbsDollar[1].unionVal}
-			}
+		fbsDollar = fbsS[fbspt-4 : fbspt+1]
+//line fbs.y:376
+		{
+			fbsVAL.enumVal = ast.NewEnumValueNode(fbsDollar[1].id, fbsDollar[2].r, fbsDollar[3].iv, fbsDollar[4].metadata)
 		}
 	case 43:
+		fbsDollar = fbsS[fbspt-1 : fbspt+1]
+//line fbs.y:381
+		{
+			fbsVAL.unionVals = fbsDollar[1].unionVals
+		}
+	case 44:
+		fbsDollar = fbsS[fbspt-2 : fbspt+1]
+//line fbs.y:384
+		{ // so the last item can have trailing ','
+			fbsDollar[1].unionVals[len(fbsDollar[1].unionVals)-1].AddComma(fbsDollar[2].r)
+			fbsVAL.unionVals = fbsDollar[1].unionVals
+		}
+	case 45:
 		fbsDollar = fbsS[fbspt-0 : fbspt+1]
-//line fbs.y:385
+//line fbs.y:388
 		{
 			fbsVAL.unionVals = nil
 		}
-	case 44:
+	case 46:
+		fbsDollar = fbsS[fbspt-1 : fbspt+1]
+//line fbs.y:393
+		{
+			fbsVAL.unionVals = []*ast.UnionValueNode{fbsDollar[1].unionVal}
+		}
+	case 47:
+		fbsDollar = fbsS[fbspt-3 : fbspt+1]
+//line fbs.y:396
+		{
+			fbsDollar[1].unionVals[len(fbsDollar[1].unionVals)-1].AddComma(fbsDollar[2].r)
+			fbsVAL.unionVals = append(fbsDollar[1].unionVals, fbsDollar[3].unionVal)
+		}
+	case 48:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:390
+//line fbs.y:402
 		{
 			fbsVAL.unionVal = ast.NewUnionValueNode(nil, nil, fbsDollar[1].typeName)
 		}
-	case 45:
+	case 49:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:393
+//line fbs.y:405
 		{
 			fbsVAL.unionVal = ast.NewUnionValueNode(fbsDollar[1].id, fbsDollar[2].r, fbsDollar[3].typeName)
 		}
-	case 46:
+	case 50:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:398
+//line fbs.y:410
 		{
 			fbsVAL.fields = []*ast.FieldNode{fbsDollar[1].field}
 		}
-	case 47:
+	case 51:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:401
+//line fbs.y:413
 		{
 			fbsVAL.fields = append(fbsDollar[1].fields, fbsDollar[2].field)
 		}
-	case 48:
+	case 52:
 		fbsDollar = fbsS[fbspt-0 : fbspt+1]
-//line fbs.y:404
+//line fbs.y:416
 		{
 			fbsVAL.fields = nil // allow empty (different from original grammar, but is needed in monster.fbs)
 		}
-	case 49:
+	case 53:
 		fbsDollar = fbsS[fbspt-5 : fbspt+1]
-//line fbs.y:409
+//line fbs.y:421
 		{
 			var opts []ast.FieldOption
 			opts = append(opts, ast.WithFieldName(fbsDollar[1].id))
@@ -1069,9 +1095,9 @@ fbsdefault:
 			opts = append(opts, ast.WithFieldSemicolon(fbsDollar[5].r))
 			fbsVAL.field = ast.NewFieldNode(opts...)
 		}
-	case 50:
+	case 54:
 		fbsDollar = fbsS[fbspt-7 : fbspt+1]
-//line fbs.y:418
+//line fbs.y:430
 		{
 			var opts []ast.FieldOption
 			opts = append(opts, ast.WithFieldName(fbsDollar[1].id))
@@ -1083,9 +1109,9 @@ fbsdefault:
 			opts = append(opts, ast.WithFieldSemicolon(fbsDollar[7].r))
 			fbsVAL.field = ast.NewFieldNode(opts...)
 		}
-	case 51:
+	case 55:
 		fbsDollar = fbsS[fbspt-7 : fbspt+1]
-//line fbs.y:429
+//line fbs.y:441
 		{ // case: "color: Color = Green;"
 			var opts []ast.FieldOption
 			opts = append(opts, ast.WithFieldName(fbsDollar[1].id))
@@ -1097,187 +1123,190 @@ fbsdefault:
 			opts = append(opts, ast.WithFieldSemicolon(fbsDollar[7].r))
 			fbsVAL.field = ast.NewFieldNode(opts...)
 		}
-	case 52:
+	case 56:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:442
+//line fbs.y:454
 		{
 			fbsVAL.metadata = ast.NewMetadataNode(fbsDollar[1].r, fbsDollar[2].metadataEntries, fbsDollar[3].r)
 		}
-	case 53:
+	case 57:
 		fbsDollar = fbsS[fbspt-0 : fbspt+1]
-//line fbs.y:445
+//line fbs.y:457
 		{
 			fbsVAL.metadata = nil
 		}
-	case 54:
+	case 58:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:450
+//line fbs.y:462
 		{
 			fbsVAL.metadataEntries = []*ast.MetadataEntryNode{fbsDollar[1].metadataEntry}
 		}
-	case 55:
+	case 59:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:453
+//line fbs.y:465
 		{
+			if len(fbsDollar[1].metadataEntries) > 0 {
+				fbsDollar[1].metadataEntries[len(fbsDollar[1].metadataEntries)-1].AddComma(fbsDollar[2].r)
+			}
 			fbsVAL.metadataEntries = append(fbsDollar[1].metadataEntries, fbsDollar[3].metadataEntry)
 		}
-	case 56:
+	case 60:
 		fbsDollar = fbsS[fbspt-0 : fbspt+1]
-//line fbs.y:456
+//line fbs.y:471
 		{
 			fbsVAL.metadataEntries = nil
 		}
-	case 57:
+	case 61:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:461
+//line fbs.y:476
 		{
 			fbsVAL.metadataEntry = ast.NewMetadataEntryNode(fbsDollar[1].id, nil, nil)
 		}
-	case 58:
+	case 62:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:464
+//line fbs.y:479
 		{
 			fbsVAL.metadataEntry = ast.NewMetadataEntryNode(fbsDollar[1].id, fbsDollar[2].r, fbsDollar[3].v)
 		}
-	case 59:
+	case 63:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:469
+//line fbs.y:484
 		{ // ast.ValueNode
 			fbsVAL.v = fbsDollar[1].v
 		}
-	case 60:
+	case 64:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:472
+//line fbs.y:487
 		{ // *ast.StringLiteralNode
 			fbsVAL.v = fbsDollar[1].s
 		}
-	case 61:
+	case 65:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:490
+//line fbs.y:505
 		{ // *ast.BoolLiteralNode
 			fbsVAL.v = fbsDollar[1].b
 		}
-	case 62:
+	case 66:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:493
+//line fbs.y:508
 		{ // ast.IntValueNode (an interface)
 			fbsVAL.v = fbsDollar[1].iv
 		}
-	case 63:
+	case 67:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:496
+//line fbs.y:511
 		{ // ast.FloatValueNode (an interface)
 			fbsVAL.v = fbsDollar[1].fv
 		}
-	case 64:
+	case 68:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:501
+//line fbs.y:516
 		{
 			fbsVAL.b = ast.NewBoolLiteralNode(fbsDollar[1].id.ToKeyword())
 		}
-	case 65:
+	case 69:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:504
+//line fbs.y:519
 		{
 			fbsVAL.b = ast.NewBoolLiteralNode(fbsDollar[1].id.ToKeyword())
 		}
-	case 66:
+	case 70:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:509
+//line fbs.y:524
 		{ // *ast.UintLiteralNode
 			fbsVAL.iv = fbsDollar[1].i
 		}
-	case 67:
+	case 71:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:512
+//line fbs.y:527
 		{ // *ast.PositiveUintLiteralNode
 			fbsVAL.iv = ast.NewPositiveUintLiteralNode(fbsDollar[1].r, fbsDollar[2].i)
 		}
-	case 68:
+	case 72:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:515
+//line fbs.y:530
 		{ // *ast.NegativeIntLiteralNode
 			fbsVAL.iv = ast.NewNegativeIntLiteralNode(fbsDollar[1].r, fbsDollar[2].i)
 		}
-	case 69:
+	case 73:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:520
+//line fbs.y:535
 		{ // *ast.FloatLiteralNode
 			fbsVAL.fv = fbsDollar[1].f
 		}
-	case 70:
+	case 74:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:523
+//line fbs.y:538
 		{ // *ast.SignedFloatLiteralNode
 			fbsVAL.fv = ast.NewSignedFloatLiteralNode(fbsDollar[1].r, fbsDollar[2].f)
 		}
-	case 71:
+	case 75:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:526
+//line fbs.y:541
 		{ // *ast.SignedFloatLiteralNode
 			fbsVAL.fv = ast.NewSignedFloatLiteralNode(fbsDollar[1].r, fbsDollar[2].f)
 		}
-	case 72:
+	case 76:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:529
+//line fbs.y:544
 		{ // *ast.SpecialFloatLiteralNode
 			fbsVAL.fv = ast.NewSpecialFloatLiteralNode(fbsDollar[1].id.ToKeyword())
 		}
-	case 73:
+	case 77:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:532
+//line fbs.y:547
 		{ // *ast.SignedFloatLiteralNode
 			f := ast.NewSpecialFloatLiteralNode(fbsDollar[2].id.ToKeyword())
 			fbsVAL.fv = ast.NewSignedFloatLiteralNode(fbsDollar[1].r, f)
 		}
-	case 74:
+	case 78:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:536
+//line fbs.y:551
 		{ // *ast.SignedFloatLiteralNode
 			f := ast.NewSpecialFloatLiteralNode(fbsDollar[2].id.ToKeyword())
 			fbsVAL.fv = ast.NewSignedFloatLiteralNode(fbsDollar[1].r, f)
 		}
-	case 75:
+	case 79:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:540
+//line fbs.y:555
 		{ // *ast.SpecialFloatLiteralNode
 			fbsVAL.fv = ast.NewSpecialFloatLiteralNode(fbsDollar[1].id.ToKeyword())
 		}
-	case 76:
+	case 80:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:543
+//line fbs.y:558
 		{ // *ast.SignedFloatLiteralNode
 			f := ast.NewSpecialFloatLiteralNode(fbsDollar[2].id.ToKeyword())
 			fbsVAL.fv = ast.NewSignedFloatLiteralNode(fbsDollar[1].r, f)
 		}
-	case 77:
+	case 81:
 		fbsDollar = fbsS[fbspt-2 : fbspt+1]
-//line fbs.y:547
+//line fbs.y:562
 		{ // *ast.SignedFloatLiteralNode
 			f := ast.NewSpecialFloatLiteralNode(fbsDollar[2].id.ToKeyword())
 			fbsVAL.fv = ast.NewSignedFloatLiteralNode(fbsDollar[1].r, f)
 		}
-	case 78:
+	case 82:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:553
+//line fbs.y:568
 		{
 			fbsVAL.typeName = ast.NewTypeNameNode(nil, fbsDollar[1].identLit, nil)
 		}
-	case 79:
+	case 83:
 		fbsDollar = fbsS[fbspt-3 : fbspt+1]
-//line fbs.y:556
+//line fbs.y:571
 		{ // [typeName] means vector of types
 			fbsVAL.typeName = ast.NewTypeNameNode(fbsDollar[1].r, fbsDollar[2].identLit, fbsDollar[3].r)
 		}
-	case 80:
+	case 84:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:561
+//line fbs.y:576
 		{ // *ast.IdentList => ast.IdentLiteralElement
 			fbsVAL.identLit = fbsDollar[1].idents.ToIdentValueNode(nil)
 		}
-	case 81:
+	case 85:
 		fbsDollar = fbsS[fbspt-1 : fbspt+1]
-//line fbs.y:564
+//line fbs.y:579
 		{
 			fbsVAL.identLit = ast.IdentLiteralElement(fbsDollar[1].id)
 		}
@@ -0,0 +1,376 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"trpc.group/trpc-go/fbs"
+)
+
+// Schema prints the .fbs source of a linked schema descriptor in canonical style and writes
+// the result to w. The source parses back to an equivalent descriptor: type references are
+// fully qualified, and declarations are grouped by namespace, so their order and the comments
+// other than documentation may differ from the original source.
+func Schema(w io.Writer, fd *fbs.SchemaDesc) error {
+	p := &descPrinter{}
+	p.printSchema(fd)
+	name := fd.Name
+	if name == "" {
+		name = "<descriptor>"
+	}
+	src, err := Source(name, p.buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("invalid schema descriptor: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// descPrinter prints descriptors to source, which is formatted afterwards.
+type descPrinter struct {
+	buf bytes.Buffer
+}
+
+func (p *descPrinter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&p.buf, format, args...)
+}
+
+func (p *descPrinter) printSchema(fd *fbs.SchemaDesc) {
+	for _, incl := range fd.Includes {
+		p.printf("include %s;\n", strconv.Quote(incl))
+	}
+	for _, attr := range fd.Attrs {
+		p.printf("attribute %s;\n", strconv.Quote(attr))
+	}
+	// The types of the empty namespace go first, since it cannot be declared.
+	namespaces := []string{""}
+	seen := map[string]bool{"": true}
+	for _, ns := range fd.Namespaces {
+		if !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	for _, ns := range namespaces {
+		if ns != "" {
+			p.printf("\nnamespace %s;\n", ns)
+		}
+		p.printTypes(fd, ns)
+	}
+	if fd.RootDesc != nil {
+		p.printf("\nroot_type %s;\n", fd.RootDesc.FullName())
+	} else if fd.Root != "" {
+		p.printf("\nroot_type %s;\n", fd.Root)
+	}
+	if fd.FileIdent != "" {
+		p.printf("file_identifier %s;\n", strconv.Quote(fd.FileIdent))
+	}
+	if fd.FileExt != "" {
+		p.printf("file_extension %s;\n", strconv.Quote(fd.FileExt))
+	}
+}
+
+// printTypes prints the types declared in namespace ns.
+func (p *descPrinter) printTypes(fd *fbs.SchemaDesc, ns string) {
+	for _, d := range fd.Enums {
+		if d.Namespace == ns {
+			p.printEnum(d)
+		}
+	}
+	for _, d := range fd.Unions {
+		if d.Namespace == ns {
+			p.printUnion(d)
+		}
+	}
+	for _, d := range fd.Structs {
+		if d.Namespace == ns {
+			p.printFields("struct", d.Name, d.Metadata, d.Documentation, d.Fields)
+		}
+	}
+	for _, d := range fd.Tables {
+		if d.Namespace == ns {
+			p.printFields("table", d.Name, d.Metadata, d.Documentation, d.Fields)
+		}
+	}
+	for _, d := range fd.RPCs {
+		if d.Namespace == ns {
+			p.printRPC(d)
+		}
+	}
+}
+
+func (p *descPrinter) printDoc(doc []string) {
+	for _, line := range doc {
+		p.printf("///%s\n", line)
+	}
+}
+
+func (p *descPrinter) printEnum(d *fbs.EnumDesc) {
+	p.printf("\n")
+	p.printDoc(d.Documentation)
+	p.printf("enum %s: %s%s {\n", d.Name, d.TypeName, metadata(d.Metadata))
+	for _, v := range d.Values {
+		p.printDoc(v.Documentation)
+		number := strconv.FormatInt(v.Number, 10)
+		if d.BaseType.IsUnsigned() {
+			number = strconv.FormatUint(v.Uint64(), 10)
+		}
+		p.printf("%s = %s%s,\n", v.Name, number, metadata(v.Metadata))
+	}
+	p.printf("}\n")
+}
+
+func (p *descPrinter) printUnion(d *fbs.UnionDesc) {
+	p.printf("\n")
+	p.printDoc(d.Documentation)
+	p.printf("union %s%s {\n", d.Name, metadata(d.Metadata))
+	for _, v := range d.Values {
+		p.printDoc(v.Documentation)
+		if v.Name == "" {
+			// The type name is kept as written, since the name of the member derives from it.
+			p.printf("%s,\n", v.MemberName())
+			continue
+		}
+		typ := strings.TrimPrefix(v.TypeName, ".")
+		if named, ok := v.TypeDesc.(fbs.NamedDesc); ok {
+			typ = named.FullName()
+		}
+		p.printf("%s: %s,\n", v.Name, typ)
+	}
+	p.printf("}\n")
+}
+
+func (p *descPrinter) printFields(keyword, name string, md *fbs.MetadataDesc, doc []string,
+	fields []*fbs.FieldDesc) {
+	p.printf("\n")
+	p.printDoc(doc)
+	p.printf("%s %s%s {\n", keyword, name, metadata(md))
+	ids := keyword == "table" && explicitIDs(fields)
+	for _, f := range fields {
+		p.printDoc(f.Documentation)
+		typ := f.TypeName
+		if named, ok := f.TypeDesc.(fbs.NamedDesc); ok {
+			typ = named.FullName()
+		}
+		if f.IsVector {
+			typ = "[" + typ + "]"
+		}
+		fmd := fieldMetadata(f)
+		if ids {
+			fmd = addAttr(fmd, "id", uint64(f.ID))
+		}
+		p.printf("%s: %s%s%s;\n", f.Name, typ, fieldDefault(f), metadata(fmd))
+	}
+	p.printf("}\n")
+}
+
+func (p *descPrinter) printRPC(d *fbs.RPCDesc) {
+	p.printf("\n")
+	p.printDoc(d.Documentation)
+	p.printf("rpc_service %s%s {\n", d.Name, metadata(d.Metadata))
+	for _, m := range d.Methods {
+		p.printDoc(m.Documentation)
+		in, out := m.InputType, m.OutputType
+		if m.InputTypeDesc != nil {
+			in = m.InputTypeDesc.FullName()
+		}
+		if m.OutputTypeDesc != nil {
+			out = m.OutputTypeDesc.FullName()
+		}
+		p.printf("%s(%s): %s%s;\n", m.Name, in, out, metadata(methodMetadata(m)))
+	}
+	p.printf("}\n")
+}
+
+// fieldDefault returns the default value of the field with a leading " = ".
+func fieldDefault(f *fbs.FieldDesc) string {
+	if f.DefaultIsNull {
+		return " = null"
+	}
+	if f.Default == nil {
+		return ""
+	}
+	if v, ok := f.Default.(*fbs.EnumValDesc); ok {
+		return " = " + v.Name
+	}
+	if v, ok := f.Default.(int64); ok && isUnsigned(f) {
+		// Defaults of ulong enums greater than math.MaxInt64 are stored in two's complement.
+		return " = " + strconv.FormatUint(uint64(v), 10)
+	}
+	return " = " + value(f.Default)
+}
+
+// isUnsigned reports whether the type of the field, or the underlying type of its enum
+// type, is an unsigned integer.
+func isUnsigned(f *fbs.FieldDesc) bool {
+	if e, ok := f.TypeDesc.(*fbs.EnumDesc); ok {
+		return e.BaseType.IsUnsigned()
+	}
+	return fbs.LookupBaseType(f.TypeName).IsUnsigned()
+}
+
+// isUnion reports whether the field is a union or a vector of union.
+func isUnion(f *fbs.FieldDesc) bool {
+	if _, ok := f.TypeDesc.(*fbs.UnionDesc); ok {
+		return true
+	}
+	return f.Type.BaseType == fbs.BaseTypeUnion || f.Type.ElementType == fbs.BaseTypeUnion
+}
+
+// explicitIDs reports whether the ids of the fields of a table must be printed: they are if
+// any field has the id attribute, or if they differ from the ids assigned in declaration
+// order. Ids which are all 0, as in descriptors built by hand, are taken as not set.
+func explicitIDs(fields []*fbs.FieldDesc) bool {
+	zero, declared := true, true
+	var id int
+	for _, f := range fields {
+		if f.Metadata.Has("id") {
+			return true
+		}
+		if isUnion(f) {
+			id++
+		}
+		zero = zero && f.ID == 0
+		declared = declared && f.ID == id
+		id++
+	}
+	return !zero && !declared
+}
+
+// methodMetadata returns the metadata of the method, adding the streaming attribute implied
+// by the flags of the descriptor if it is missing.
+func methodMetadata(m *fbs.MethodDesc) *fbs.MetadataDesc {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return addAttr(m.Metadata, fbs.Streaming, fbs.BidiStreaming)
+	case m.ClientStreaming:
+		return addAttr(m.Metadata, fbs.Streaming, fbs.ClientStreaming)
+	case m.ServerStreaming:
+		return addAttr(m.Metadata, fbs.Streaming, fbs.ServerStreaming)
+	}
+	return m.Metadata
+}
+
+// fieldMetadata returns the metadata of the field, adding the attributes implied by the
+// flags of the descriptor if they are missing, which happens to descriptors built by hand.
+func fieldMetadata(f *fbs.FieldDesc) *fbs.MetadataDesc {
+	md := f.Metadata
+	add := func(key string, val interface{}) {
+		md = addAttr(md, key, val)
+	}
+	if f.IsRequired {
+		add("required", nil)
+	}
+	if f.IsDeprecated {
+		add("deprecated", nil)
+	}
+	if f.IsKey {
+		add("key", nil)
+	}
+	if f.Hash != "" {
+		add("hash", f.Hash)
+	}
+	if f.NestedFlatbuffer != nil {
+		add("nested_flatbuffer", f.NestedFlatbuffer.FullName())
+	}
+	return md
+}
+
+// addAttr returns a copy of md with the attribute key added, or md if it has it already.
+func addAttr(md *fbs.MetadataDesc, key string, val interface{}) *fbs.MetadataDesc {
+	if md.Has(key) {
+		return md
+	}
+	res := &fbs.MetadataDesc{KV: map[string]interface{}{}}
+	res.Keys = metadataKeys(md)
+	if md != nil {
+		for k, v := range md.KV {
+			res.KV[k] = v
+		}
+	}
+	res.Keys = append(res.Keys, key)
+	res.KV[key] = val
+	return res
+}
+
+// metadataKeys returns the keys of the attributes in order. They are sorted if the order is
+// not given, as in descriptors built by hand.
+func metadataKeys(md *fbs.MetadataDesc) []string {
+	if md == nil {
+		return nil
+	}
+	if len(md.Keys) > 0 {
+		return append([]string(nil), md.Keys...)
+	}
+	keys := make([]string, 0, len(md.KV))
+	for key := range md.KV {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// metadata returns the metadata with a leading space if it is not empty.
+func metadata(md *fbs.MetadataDesc) string {
+	keys := metadataKeys(md)
+	if len(keys) == 0 {
+		return ""
+	}
+	entries := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if v := md.KV[key]; v != nil {
+			entries = append(entries, key+": "+value(v))
+		} else {
+			entries = append(entries, key)
+		}
+	}
+	return " (" + strings.Join(entries, ", ") + ")"
+}
+
+// value returns the literal of the value of a default or an attribute.
+func value(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "nan"
+		case math.IsInf(v, 1):
+			return "inf"
+		case math.IsInf(v, -1):
+			return "-inf"
+		}
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			// Keep it a floating point literal.
+			s += ".0"
+		}
+		return s
+	}
+	return fmt.Sprint(v)
+}
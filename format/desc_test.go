@@ -0,0 +1,241 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package format_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"trpc.group/trpc-go/fbs"
+	"trpc.group/trpc-go/fbs/format"
+)
+
+func TestSchemaRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fbs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{
+		"monster_test.fbs",
+		"simple_test1.fbs",
+		"optional_scalars.fbs",
+		"union_vector.fbs",
+		"enum_test.fbs",
+		"annotated_test.fbs",
+	} {
+		fds, err := fbs.NewParser("../fbsfiles").ParseFiles(name)
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+		var buf bytes.Buffer
+		assert.Nil(t, format.Schema(&buf, fds[0]), name)
+		filename := filepath.Join(dir, name)
+		assert.Nil(t, ioutil.WriteFile(filename, buf.Bytes(), 0644))
+		printed, err := fbs.NewParser("../fbsfiles").ParseFiles(filename)
+		if !assert.Nil(t, err, "%s:\n%s", name, buf.String()) {
+			continue
+		}
+		assert.Equal(t, dumpSchema(fds[0]), dumpSchema(printed[0]), name)
+		// Printing is stable.
+		var again bytes.Buffer
+		assert.Nil(t, format.Schema(&again, printed[0]))
+		assert.Equal(t, buf.String(), again.String(), name)
+	}
+}
+
+func TestSchemaByHand(t *testing.T) {
+	table := &fbs.TableDesc{Name: "T", Namespace: "a.b"}
+	table.Fields = []*fbs.FieldDesc{
+		{Parent: table, Name: "name", TypeName: "string", IsKey: true},
+		{Parent: table, Name: "old", TypeName: "int", IsDeprecated: true, Default: int64(-1)},
+		{Parent: table, Name: "ratio", TypeName: "float", Default: float64(2)},
+		{Parent: table, Name: "data", TypeName: "ubyte", IsVector: true, NestedFlatbuffer: table},
+	}
+	fd := &fbs.SchemaDesc{
+		Namespaces: []string{"", "a.b"},
+		Tables:     []*fbs.TableDesc{table},
+		RootDesc:   table,
+		FileIdent:  "TTTT",
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, format.Schema(&buf, fd))
+	want := `
+namespace a.b;
+
+table T {
+  name:  string   (key);
+  old:   int = -1 (deprecated);
+  ratio: float = 2.0;
+  data:  [ubyte]  (nested_flatbuffer: "a.b.T");
+}
+
+root_type a.b.T;
+file_identifier "TTTT";
+`
+	assert.Equal(t, strings.TrimPrefix(want, "\n"), buf.String())
+
+	bad := &fbs.SchemaDesc{Tables: []*fbs.TableDesc{{Name: "has space"}}}
+	assert.NotNil(t, format.Schema(&buf, bad))
+}
+
+func TestSchemaByHandParses(t *testing.T) {
+	attrs := func(kv map[string]interface{}) *fbs.MetadataDesc {
+		return &fbs.MetadataDesc{KV: kv}
+	}
+	color := &fbs.EnumDesc{Namespace: "a", Name: "Color", TypeName: "ulong", BaseType: fbs.BaseTypeUlong}
+	color.Values = []*fbs.EnumValDesc{{Parent: color, Name: "Red", Number: 1}, {Parent: color, Name: "Max", Number: -1}}
+	// Attributes without Keys, ids given by attributes.
+	req := &fbs.TableDesc{Namespace: "a", Name: "Req", Metadata: attrs(map[string]interface{}{"private": nil})}
+	req.Fields = []*fbs.FieldDesc{
+		{Parent: req, Name: "b", TypeName: "int", Metadata: attrs(map[string]interface{}{"id": uint64(2)})},
+		{Parent: req, Name: "max", TypeName: "Color", TypeDesc: color, Default: int64(-1),
+			Metadata: attrs(map[string]interface{}{"id": uint64(0)})},
+		{Parent: req, Name: "other", TypeName: "Color", TypeDesc: color, Default: int64(-2),
+			Metadata: attrs(map[string]interface{}{"id": uint64(1), "deprecated": nil})},
+	}
+	// Ids given by the descriptors only.
+	rsp := &fbs.TableDesc{Namespace: "a", Name: "Rsp"}
+	rsp.Fields = []*fbs.FieldDesc{
+		{Parent: rsp, Name: "x", TypeName: "int", ID: 1},
+		{Parent: rsp, Name: "y", TypeName: "ulong", ID: 0, Default: int64(-1)},
+	}
+	rpc := &fbs.RPCDesc{Namespace: "a", Name: "S"}
+	for _, m := range []struct {
+		name           string
+		client, server bool
+	}{{"Unary", false, false}, {"Client", true, false}, {"Server", false, true}, {"Bidi", true, true}} {
+		rpc.Methods = append(rpc.Methods, &fbs.MethodDesc{Parent: rpc, Name: m.name, InputTypeDesc: req,
+			OutputTypeDesc: rsp, ClientStreaming: m.client, ServerStreaming: m.server})
+	}
+	fd := &fbs.SchemaDesc{
+		Name:       "hand.fbs",
+		Namespaces: []string{"", "a"},
+		Attrs:      []string{"private"},
+		Tables:     []*fbs.TableDesc{req, rsp},
+		Enums:      []*fbs.EnumDesc{color},
+		RPCs:       []*fbs.RPCDesc{rpc},
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, format.Schema(&buf, fd))
+	src := buf.String()
+	assert.Contains(t, src, "table Req (private) {")
+	assert.Contains(t, src, "max:   a.Color = 18446744073709551615 (id: 0);")
+	assert.Contains(t, src, "other: a.Color = 18446744073709551614 (deprecated, id: 1);")
+	assert.Contains(t, src, "Server(a.Req): a.Rsp (streaming: \"server\");")
+
+	p := fbs.NewParser()
+	p.SetAccessor(fbs.SourceAccessorFromMap(map[string]string{"hand.fbs": src}))
+	fds, err := p.ParseFiles("hand.fbs")
+	if !assert.Nil(t, err, src) {
+		return
+	}
+	parsed := fds[0]
+	assert.True(t, parsed.Tables[0].Metadata.Has("private"))
+	fields := parsed.Tables[0].Fields
+	assert.Equal(t, []int{2, 0, 1}, []int{fields[0].ID, fields[1].ID, fields[2].ID})
+	assert.Equal(t, parsed.Enums[0].Values[1], fields[1].Default)
+	assert.Equal(t, int64(-2), fields[2].Default)
+	assert.True(t, fields[2].IsDeprecated)
+	fields = parsed.Tables[1].Fields
+	assert.Equal(t, []int{1, 0}, []int{fields[0].ID, fields[1].ID})
+	assert.Equal(t, uint64(math.MaxUint64), fields[1].Default)
+	for i, m := range parsed.RPCs[0].Methods {
+		assert.Equal(t, rpc.Methods[i].ClientStreaming, m.ClientStreaming, m.Name)
+		assert.Equal(t, rpc.Methods[i].ServerStreaming, m.ServerStreaming, m.Name)
+	}
+}
+
+// dumpSchema describes the schema in text, with all type references fully qualified.
+func dumpSchema(fd *fbs.SchemaDesc) string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	add("includes %v attrs %v root %s ident %q ext %q", fd.Includes, fd.Attrs, fullName(fd.RootDesc),
+		fd.FileIdent, fd.FileExt)
+	for _, d := range fd.Tables {
+		dumpFields(add, d.FullName(), d.Metadata, d.Documentation, d.Fields)
+	}
+	for _, d := range fd.Structs {
+		dumpFields(add, d.FullName(), d.Metadata, d.Documentation, d.Fields)
+	}
+	for _, d := range fd.Enums {
+		add("enum %s %s %s %s %q", d.FullName(), d.TypeName, d.BaseType, dumpMetadata(d.Metadata), d.Documentation)
+		for _, v := range d.Values {
+			add("enum value %s %d %s %q", v.FullName(), v.Number, dumpMetadata(v.Metadata), v.Documentation)
+		}
+	}
+	for _, d := range fd.Unions {
+		add("union %s %s %q", d.FullName(), dumpMetadata(d.Metadata), d.Documentation)
+		for _, v := range d.Values {
+			add("union value %s %s %s %d %q", v.FullName(), v.TypeName, fullName(v.TypeDesc), v.Number, v.Documentation)
+		}
+	}
+	for _, d := range fd.RPCs {
+		add("rpc %s %s %q", d.FullName(), dumpMetadata(d.Metadata), d.Documentation)
+		for _, m := range d.Methods {
+			add("method %s %s %s %v %v %s %q", m.FullName(), fullName(m.InputTypeDesc), fullName(m.OutputTypeDesc),
+				m.ClientStreaming, m.ServerStreaming, dumpMetadata(m.Metadata), m.Documentation)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func dumpFields(add func(string, ...interface{}), name string, md *fbs.MetadataDesc, doc []string,
+	fields []*fbs.FieldDesc) {
+	add("%s %s %q", name, dumpMetadata(md), doc)
+	for _, f := range fields {
+		def := fmt.Sprintf("%T %v", f.Default, f.Default)
+		if v, ok := f.Default.(*fbs.EnumValDesc); ok {
+			def = v.FullName()
+		}
+		add("field %s %v %s %v %s null %v id %d %v %v %v %v %s %s %s %q", f.FullName(), f.Type, fullName(f.TypeDesc),
+			f.IsVector, def, f.DefaultIsNull, f.ID, f.IsRequired, f.IsDeprecated, f.IsKey, f.IsOptional, f.Hash,
+			fullName(f.NestedFlatbuffer), dumpMetadata(f.Metadata), f.Documentation)
+	}
+}
+
+func dumpMetadata(md *fbs.MetadataDesc) string {
+	if md == nil {
+		return "()"
+	}
+	var entries []string
+	for _, k := range md.Keys {
+		entries = append(entries, fmt.Sprintf("%s=%T(%v)", k, md.KV[k], md.KV[k]))
+	}
+	return "(" + strings.Join(entries, ",") + ")"
+}
+
+func fullName(d fbs.Desc) string {
+	if named, ok := d.(fbs.NamedDesc); ok && !isNil(named) {
+		return named.FullName()
+	}
+	return "-"
+}
+
+func isNil(d fbs.NamedDesc) bool {
+	switch v := d.(type) {
+	case *fbs.TableDesc:
+		return v == nil
+	}
+	return false
+}
@@ -0,0 +1,576 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+// Package format implements canonical formatting of .fbs source files.
+//
+// The canonical style is:
+//  1. two spaces of indentation inside tables, structs, enums, unions and rpc services;
+//  2. one member per line, with field types, default values, attributes and trailing
+//     comments aligned among consecutive lines;
+//  3. `name: type` and `key: value` spacing, and `, ` between metadata entries;
+//  4. a trailing comma after every enum value and union member;
+//  5. blank lines between declarations and members are kept, but collapsed to one.
+//
+// All comments are kept.
+package format
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"trpc.group/trpc-go/fbs"
+	"trpc.group/trpc-go/fbs/ast"
+)
+
+const indentation = "  "
+
+// Source formats the .fbs source src in canonical style. The filename is only used in
+// error messages. If src has syntax errors, the error is returned.
+func Source(filename string, src []byte) ([]byte, error) {
+	schema, err := fbs.ParseSchema(filename, bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := Node(&buf, schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Node formats the syntax tree of a .fbs file in canonical style and writes the result to w.
+// The tree should be produced by fbs.ParseSchema, so that all the comments are available.
+func Node(w io.Writer, schema *ast.SchemaNode) error {
+	p := &printer{}
+	p.printSchema(schema)
+	_, err := w.Write(p.bytes())
+	return err
+}
+
+// row is a line of output. Cells of consecutive rows are aligned to each other.
+type row struct {
+	blank    bool     // whether the row is preceded by a blank line
+	comments []string // comments printed on their own lines before the row
+	prefix   string   // comments before the first cell on the same line
+	cells    []string // aligned cells, empty cells are skipped
+	suffix   string   // text appended right after the last non-empty cell
+	trailing string   // trailing comments
+}
+
+// multiline reports whether the row spans multiple lines, which happens when a line comment
+// is found in the middle of a declaration. Such rows are not aligned.
+func (r *row) multiline() bool {
+	for _, c := range r.cells {
+		if strings.Contains(c, "\n") {
+			return true
+		}
+	}
+	return false
+}
+
+// last returns the index of the last non-empty cell.
+func (r *row) last() int {
+	for i := len(r.cells) - 1; i >= 0; i-- {
+		if r.cells[i] != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+type printer struct {
+	buf bytes.Buffer
+	// lastLine is the line in the source where the last consumed token or comment ends,
+	// used to find the blank lines of the source.
+	lastLine int
+	// first and last are the tokens of the current row, whose leading and trailing comments
+	// are printed around the row instead of inside of it.
+	first, last ast.TerminalNode
+	// pending stores the rows of the current block.
+	pending []*row
+	depth   int
+	// blockStart is set after an opening brace, where blank lines are dropped.
+	blockStart bool
+}
+
+func (p *printer) bytes() []byte {
+	return p.buf.Bytes()
+}
+
+func (p *printer) printSchema(schema *ast.SchemaNode) {
+	for _, incl := range schema.Includes {
+		r := p.newRow(incl, incl.Semicolon)
+		r.cells = []string{p.node(incl.Keyword) + " " + p.node(incl.Name)}
+		r.suffix = p.node(incl.Semicolon)
+		p.endRow(r, incl.Semicolon)
+	}
+	for _, decl := range schema.Decls {
+		p.printDecl(decl)
+	}
+	if schema.EOF != nil {
+		p.flushComments(schema.EOF)
+	}
+	p.flush()
+}
+
+func (p *printer) printDecl(decl ast.DeclElement) {
+	switch n := decl.(type) {
+	case *ast.NamespaceDeclNode:
+		p.simpleDecl(n, n.Keyword, n.Name, n.Semicolon)
+	case *ast.RootDeclNode:
+		p.simpleDecl(n, n.Keyword, n.Name, n.Semicolon)
+	case *ast.FileExtDeclNode:
+		p.simpleDecl(n, n.Keyword, n.Name, n.Semicolon)
+	case *ast.FileIdentDeclNode:
+		p.simpleDecl(n, n.Keyword, n.Name, n.Semicolon)
+	case *ast.AttrDeclNode:
+		p.simpleDecl(n, n.Keyword, n.Name, n.Semicolon)
+	case *ast.TableDeclNode:
+		p.fieldsDecl(n, n.Keyword, n.Name, n.Metadata, n.OpenBrace, n.Fields, n.CloseBrace)
+	case *ast.StructDeclNode:
+		p.fieldsDecl(n, n.Keyword, n.Name, n.Metadata, n.OpenBrace, n.Fields, n.CloseBrace)
+	case *ast.EnumDeclNode:
+		p.enumDecl(n)
+	case *ast.UnionDeclNode:
+		p.unionDecl(n)
+	case *ast.RPCDeclNode:
+		p.rpcDecl(n)
+	}
+}
+
+// simpleDecl prints declarations of the form `keyword name;`.
+func (p *printer) simpleDecl(n ast.Node, keyword *ast.KeywordNode, name ast.Node, semicolon *ast.RuneNode) {
+	r := p.newRow(n, semicolon)
+	r.cells = []string{p.node(keyword) + " " + p.node(name)}
+	r.suffix = p.node(semicolon)
+	p.endRow(r, semicolon)
+}
+
+func (p *printer) fieldsDecl(n ast.Node, keyword *ast.KeywordNode, name *ast.IdentNode,
+	metadata *ast.MetadataNode, openBrace *ast.RuneNode, fields []*ast.FieldNode, closeBrace *ast.RuneNode) {
+	r := p.newRow(n, openBrace)
+	r.cells = []string{p.node(keyword) + " " + p.node(name) + p.metadata(metadata) + " " + p.node(openBrace)}
+	p.openBlock(r, openBrace)
+	for _, f := range fields {
+		last := ast.LastToken(f)
+		fr := p.newRow(f, last)
+		typ := p.node(f.TypeName)
+		if f.Equal != nil {
+			typ += " " + p.node(f.Equal) + " " + p.node(f.Scalar)
+		}
+		fr.cells = []string{p.node(f.Name) + p.node(f.Colon), typ, strings.TrimPrefix(p.metadata(f.Metadata), " ")}
+		fr.suffix = p.node(f.Semicolon)
+		p.endRow(fr, last)
+	}
+	p.closeBlock(openBrace, closeBrace)
+}
+
+func (p *printer) enumDecl(n *ast.EnumDeclNode) {
+	r := p.newRow(n, n.OpenBrace)
+	r.cells = []string{p.node(n.Keyword) + " " + p.node(n.Name) + p.node(n.Colon) + " " + p.node(n.TypeName) +
+		p.metadata(n.Metadata) + " " + p.node(n.OpenBrace)}
+	p.openBlock(r, n.OpenBrace)
+	for _, v := range n.Decls {
+		last := ast.LastToken(v)
+		vr := p.newRow(v, last)
+		vr.cells = []string{p.node(v.Name), "", strings.TrimPrefix(p.metadata(v.Metadata), " ")}
+		if v.Equal != nil {
+			vr.cells[1] = p.node(v.Equal) + " " + p.node(v.IntVal)
+		}
+		vr.suffix = p.comma(v.Comma)
+		p.endRow(vr, last)
+	}
+	p.closeBlock(n.OpenBrace, n.CloseBrace)
+}
+
+func (p *printer) unionDecl(n *ast.UnionDeclNode) {
+	r := p.newRow(n, n.OpenBrace)
+	r.cells = []string{p.node(n.Keyword) + " " + p.node(n.Name) + p.metadata(n.Metadata) + " " + p.node(n.OpenBrace)}
+	p.openBlock(r, n.OpenBrace)
+	for _, v := range n.Decls {
+		last := ast.LastToken(v)
+		vr := p.newRow(v, last)
+		if v.Colon != nil {
+			vr.cells = []string{p.node(v.Name) + p.node(v.Colon), p.node(v.TypeName)}
+		} else {
+			vr.cells = []string{p.node(v.TypeName)}
+		}
+		vr.suffix = p.comma(v.Comma)
+		p.endRow(vr, last)
+	}
+	p.closeBlock(n.OpenBrace, n.CloseBrace)
+}
+
+func (p *printer) rpcDecl(n *ast.RPCDeclNode) {
+	r := p.newRow(n, n.OpenBrace)
+	r.cells = []string{p.node(n.Keyword) + " " + p.node(n.Name) + p.metadata(n.Metadata) + " " + p.node(n.OpenBrace)}
+	p.openBlock(r, n.OpenBrace)
+	for _, m := range n.Methods {
+		mr := p.newRow(m, m.Semicolon)
+		mr.cells = []string{
+			p.node(m.Name) + p.node(m.OpenParen) + p.node(m.ReqName) + p.node(m.CloseParen) + p.node(m.Colon),
+			p.node(m.RspName),
+			strings.TrimPrefix(p.metadata(m.Metadata), " "),
+		}
+		mr.suffix = p.node(m.Semicolon)
+		p.endRow(mr, m.Semicolon)
+	}
+	p.closeBlock(n.OpenBrace, n.CloseBrace)
+}
+
+// openBlock prints the header row of a declaration with braces.
+func (p *printer) openBlock(r *row, openBrace *ast.RuneNode) {
+	p.endRow(r, openBrace)
+	p.flush()
+	p.depth++
+	p.blockStart = true
+}
+
+// closeBlock prints the closing brace of a declaration. A block without members and comments
+// is printed on the header line.
+func (p *printer) closeBlock(openBrace, closeBrace *ast.RuneNode) {
+	empty := len(p.pending) == 0 && len(closeBrace.LeadingComments()) == 0 &&
+		len(openBrace.TrailingComments()) == 0
+	if empty {
+		p.depth--
+		p.blockStart = false
+		p.buf.Truncate(p.buf.Len() - 1) // the newline after the header
+		p.lastLine = closeBrace.End().Line
+		p.buf.WriteString(closeBrace.RawText())
+		if trailing := p.trailing(closeBrace); trailing != "" {
+			p.buf.WriteByte(' ')
+			p.buf.WriteString(trailing)
+		}
+		p.buf.WriteByte('\n')
+		return
+	}
+	p.flushComments(closeBrace)
+	p.flush()
+	p.depth--
+	p.blockStart = false
+	r := &row{cells: []string{closeBrace.RawText()}}
+	p.lastLine = closeBrace.End().Line
+	r.trailing = p.trailing(closeBrace)
+	p.pending = append(p.pending, r)
+	p.flush()
+}
+
+// newRow starts a row for node n, whose last token is last. The leading comments of the
+// first token of n are attached to the row.
+func (p *printer) newRow(n ast.Node, last ast.TerminalNode) *row {
+	first := ast.FirstToken(n)
+	r := p.startRow(first)
+	r.prefix = p.leading(r, first)
+	p.first, p.last = first, last
+	return r
+}
+
+// startRow creates a row that starts with tok or its leading comments.
+func (p *printer) startRow(tok ast.TerminalNode) *row {
+	line := tok.Start().Line
+	if comments := tok.LeadingComments(); len(comments) > 0 {
+		line = comments[0].Start.Line
+	}
+	r := &row{blank: !p.blockStart && p.blankBefore(line)}
+	p.blockStart = false
+	return r
+}
+
+// endRow completes the row with the trailing comments of its last token.
+func (p *printer) endRow(r *row, last ast.TerminalNode) {
+	p.lastLine = last.End().Line
+	r.trailing = p.trailing(last)
+	p.first, p.last = nil, nil
+	p.pending = append(p.pending, r)
+}
+
+// leading consumes the leading comments of tok. Comments on their own lines are added to
+// r.comments, and block comments on the same line as tok are returned as a prefix.
+func (p *printer) leading(r *row, tok ast.TerminalNode) string {
+	tokLine := tok.Start().Line
+	comments := tok.LeadingComments()
+	var prefix []string
+	for i, c := range comments {
+		nextLine := tokLine
+		if i+1 < len(comments) {
+			nextLine = comments[i+1].Start.Line
+		}
+		text := commentText(c)
+		if !isLineComment(c) && c.End.Line == nextLine {
+			prefix = append(prefix, text)
+			p.lastLine = c.End.Line
+			continue
+		}
+		if len(prefix) > 0 {
+			text = strings.Join(prefix, " ") + " " + text
+			prefix = nil
+		}
+		if len(r.comments) > 0 && p.blankBefore(c.Start.Line) {
+			r.comments = append(r.comments, "")
+		}
+		r.comments = append(r.comments, text)
+		p.lastLine = c.End.Line
+	}
+	if len(r.comments) > 0 && p.blankBefore(tokLine) && len(prefix) == 0 {
+		r.comments = append(r.comments, "")
+	}
+	return strings.Join(prefix, " ")
+}
+
+// flushComments adds the leading comments of tok, which is a closing brace or the end of
+// file, as rows of their own.
+func (p *printer) flushComments(tok ast.TerminalNode) {
+	r := p.startRow(tok)
+	prefix := p.leading(r, tok)
+	if prefix != "" {
+		r.comments = append(r.comments, prefix)
+	}
+	if len(r.comments) == 0 {
+		return
+	}
+	if r.comments[len(r.comments)-1] == "" {
+		r.comments = r.comments[:len(r.comments)-1]
+	}
+	// The comments are printed by a row without cells.
+	p.pending = append(p.pending, r)
+}
+
+// blankBefore reports whether there is a blank line between the last consumed source and line.
+func (p *printer) blankBefore(line int) bool {
+	return p.lastLine > 0 && line-p.lastLine > 1
+}
+
+// trailing returns the trailing comments of tok.
+func (p *printer) trailing(tok ast.TerminalNode) string {
+	var texts []string
+	for _, c := range tok.TrailingComments() {
+		texts = append(texts, commentText(c))
+		p.lastLine = c.End.Line
+	}
+	return strings.Join(texts, " ")
+}
+
+// node prints the tokens of n without spaces in between, keeping the comments inside of the
+// current row.
+func (p *printer) node(n ast.Node) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	ast.Inspect(n, func(n ast.Node) bool {
+		t, ok := n.(ast.TerminalNode)
+		if !ok {
+			return true
+		}
+		if t != p.first {
+			for _, c := range t.LeadingComments() {
+				p.comment(&b, c)
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(t.RawText())
+		if t != p.last {
+			for _, c := range t.TrailingComments() {
+				b.WriteByte(' ')
+				p.comment(&b, c)
+			}
+		}
+		return true
+	})
+	return b.String()
+}
+
+// comment writes a comment inside of a row. A line comment ends the line, so the rest of
+// the row continues on the next line with an extra indentation.
+func (p *printer) comment(b *strings.Builder, c ast.Comment) {
+	b.WriteString(commentText(c))
+	if isLineComment(c) {
+		b.WriteByte('\n')
+		b.WriteString(strings.Repeat(indentation, p.depth+1))
+	}
+}
+
+// metadata prints metadata, with a leading space if it is not empty.
+func (p *printer) metadata(n *ast.MetadataNode) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte(' ')
+	b.WriteString(p.node(n.OpenParen))
+	for _, e := range n.Entries {
+		b.WriteString(p.node(e.Key))
+		if e.Colon != nil {
+			b.WriteString(p.node(e.Colon))
+			b.WriteByte(' ')
+			b.WriteString(p.node(e.Value))
+		}
+		if e.Comma != nil {
+			b.WriteString(p.node(e.Comma))
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteString(p.node(n.CloseParen))
+	return b.String()
+}
+
+// comma prints the comma after enum values and union members, adding it if missing.
+func (p *printer) comma(comma *ast.RuneNode) string {
+	if comma == nil {
+		return ","
+	}
+	return p.node(comma)
+}
+
+// displayWidth returns the number of columns s takes in a terminal, counting the East Asian
+// wide characters, such as CJK ideographs, as two columns.
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w++
+		if isWide(r) {
+			w++
+		}
+	}
+	return w
+}
+
+// isWide tells whether r is an East Asian wide or fullwidth character.
+func isWide(r rune) bool {
+	switch {
+	case r < 0x1100:
+		return false
+	case r <= 0x115f, // Hangul Jamo
+		r >= 0x2e80 && r <= 0x303e, // CJK radicals, Kangxi radicals, CJK symbols and punctuation
+		r >= 0x3041 && r <= 0x33ff, // Hiragana, Katakana, Bopomofo, CJK compatibility
+		r >= 0x3400 && r <= 0x4dbf, // CJK unified ideographs extension A
+		r >= 0x4e00 && r <= 0x9fff, // CJK unified ideographs
+		r >= 0xa000 && r <= 0xa4cf, // Yi
+		r >= 0xac00 && r <= 0xd7a3, // Hangul syllables
+		r >= 0xf900 && r <= 0xfaff, // CJK compatibility ideographs
+		r >= 0xfe30 && r <= 0xfe4f, // CJK compatibility forms
+		r >= 0xff00 && r <= 0xff60, // Fullwidth forms
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x1f300 && r <= 0x1f64f, // Emoji
+		r >= 0x1f900 && r <= 0x1f9ff,
+		r >= 0x20000 && r <= 0x3fffd: // CJK unified ideographs extensions B and beyond
+		return true
+	}
+	return false
+}
+
+// flush writes the pending rows, aligning the cells of consecutive rows.
+func (p *printer) flush() {
+	rows := p.pending
+	p.pending = nil
+	for start := 0; start < len(rows); {
+		end := start + 1
+		if !rows[start].multiline() {
+			for end < len(rows) && !rows[end].blank && !rows[end].multiline() {
+				end++
+			}
+		}
+		p.writeSection(rows[start:end])
+		start = end
+	}
+}
+
+// writeSection writes rows aligned to each other.
+func (p *printer) writeSection(rows []*row) {
+	var widths []int
+	for _, r := range rows {
+		for i := 0; i < r.last(); i++ {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := displayWidth(r.cells[i]); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	indent := strings.Repeat(indentation, p.depth)
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		var b strings.Builder
+		w := 0 // display width of b.
+		if r.prefix != "" {
+			// The prefix is not aligned, and shifts the rest of the row.
+			b.WriteString(r.prefix)
+			b.WriteByte(' ')
+			w = displayWidth(r.prefix) + 1
+		}
+		col := w
+		for j := 0; j <= r.last(); j++ {
+			if r.cells[j] != "" {
+				for ; w < col; w++ {
+					b.WriteByte(' ')
+				}
+				b.WriteString(r.cells[j])
+				w += displayWidth(r.cells[j])
+			}
+			if j < len(widths) && widths[j] > 0 {
+				col += widths[j] + 1
+			}
+		}
+		b.WriteString(r.suffix)
+		lines[i] = b.String()
+	}
+	// Align trailing comments of consecutive rows.
+	for i := 0; i < len(rows); {
+		if rows[i].trailing == "" || rows[i].cells == nil {
+			i++
+			continue
+		}
+		j, width := i, 0
+		for ; j < len(rows) && rows[j].trailing != "" && rows[j].cells != nil &&
+			(j == i || len(rows[j].comments) == 0); j++ {
+			if w := displayWidth(lines[j]); w > width {
+				width = w
+			}
+		}
+		for k := i; k < j; k++ {
+			lines[k] += strings.Repeat(" ", width-displayWidth(lines[k])+1) + rows[k].trailing
+		}
+		i = j
+	}
+	for i, r := range rows {
+		if r.blank && p.buf.Len() > 0 {
+			p.buf.WriteByte('\n')
+		}
+		for _, c := range r.comments {
+			if c != "" {
+				p.buf.WriteString(indent)
+				p.buf.WriteString(c)
+			}
+			p.buf.WriteByte('\n')
+		}
+		if r.cells == nil && r.trailing == "" {
+			continue
+		}
+		p.buf.WriteString(indent)
+		p.buf.WriteString(lines[i])
+		p.buf.WriteByte('\n')
+	}
+}
+
+func isLineComment(c ast.Comment) bool {
+	return strings.HasPrefix(c.Text, "//")
+}
+
+func commentText(c ast.Comment) string {
+	if isLineComment(c) {
+		return strings.TrimRight(c.Text, "\r\n \t")
+	}
+	return c.Text
+}
@@ -0,0 +1,108 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package format_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"trpc.group/trpc-go/fbs"
+	"trpc.group/trpc-go/fbs/ast"
+	"trpc.group/trpc-go/fbs/format"
+)
+
+func TestSource(t *testing.T) {
+	src, err := ioutil.ReadFile("../fbsfiles/format_test/format_test.fbs")
+	assert.Nil(t, err)
+	want, err := ioutil.ReadFile("../fbsfiles/format_test/format_test.golden")
+	assert.Nil(t, err)
+	got, err := format.Source("format_test.fbs", src)
+	assert.Nil(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestSourceError(t *testing.T) {
+	_, err := format.Source("error.fbs", []byte("table T {"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "error.fbs")
+}
+
+func TestSourceEmpty(t *testing.T) {
+	got, err := format.Source("empty.fbs", nil)
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+	got, err = format.Source("comment.fbs", []byte("\n\n// only a comment\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "// only a comment\n", string(got))
+}
+
+// TestIdempotent formats every file in fbsfiles/, checking that formatting is idempotent and
+// that no token or comment is lost.
+func TestIdempotent(t *testing.T) {
+	err := filepath.Walk("../fbsfiles", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".fbs") {
+			return err
+		}
+		src, err := ioutil.ReadFile(path)
+		assert.Nil(t, err)
+		schema, err := fbs.ParseSchema(path, bytes.NewReader(src))
+		if err != nil {
+			// Some of the error test files have syntax errors on purpose.
+			return nil
+		}
+		out, err := format.Source(path, src)
+		if !assert.Nil(t, err, path) {
+			return nil
+		}
+		again, err := format.Source(path, out)
+		assert.Nil(t, err, path)
+		assert.Equal(t, string(out), string(again), path)
+		formatted, err := fbs.ParseSchema(path, bytes.NewReader(out))
+		assert.Nil(t, err, path)
+		wantTokens, wantComments := collect(schema)
+		gotTokens, gotComments := collect(formatted)
+		assert.Equal(t, wantTokens, gotTokens, path)
+		assert.Equal(t, wantComments, gotComments, path)
+		return nil
+	})
+	assert.Nil(t, err)
+}
+
+// collect returns the tokens, except for commas which the formatter may add, and the
+// comments of the schema.
+func collect(schema *ast.SchemaNode) ([]string, []string) {
+	var tokens, comments []string
+	addComments := func(cs []ast.Comment) {
+		for _, c := range cs {
+			comments = append(comments, strings.TrimSpace(c.Text))
+		}
+	}
+	ast.Inspect(schema, func(n ast.Node) bool {
+		if t, ok := n.(ast.TerminalNode); ok {
+			addComments(t.LeadingComments())
+			if t.RawText() != "," {
+				tokens = append(tokens, t.RawText())
+			}
+			addComments(t.TrailingComments())
+		}
+		return true
+	})
+	addComments(schema.EOF.LeadingComments())
+	return tokens, comments
+}
@@ -0,0 +1,52 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+//go:build go1.16
+// +build go1.16
+
+package fbs
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+)
+
+// NewFSParser creates a parser reading the files from fsys, such as an embed.FS. File names
+// and include paths are slash-separated paths within fsys, the root of fsys being the
+// current directory. Example:
+//
+//	//go:embed schemas
+//	var schemas embed.FS
+//
+//	p := fbs.NewFSParser(schemas, "schemas/common")
+//	fds, err := p.ParseFiles("schemas/greeter.fbs")
+func NewFSParser(fsys fs.FS, includes ...string) *Parser {
+	p := NewParser(includes...)
+	p.Accessor = FSAccessor(fsys)
+	return p
+}
+
+// FSAccessor returns a file accessor opening the files from fsys. File names are cleaned, so
+// that "./a.fbs" and "a.fbs" are the same file. Names which are not valid within fsys, such
+// as the ones going up from the root, are reported as missing files.
+func FSAccessor(fsys fs.FS) FileAccessor {
+	return func(filename string) (io.ReadCloser, error) {
+		name := path.Clean(filepath.ToSlash(filename))
+		if !fs.ValidPath(name) {
+			return nil, &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+		}
+		return fsys.Open(name)
+	}
+}
@@ -0,0 +1,49 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+//go:build go1.16
+// +build go1.16
+
+package fbs
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSParse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/greeter.fbs": {Data: []byte("include \"hello.fbs\";\ninclude \"types.fbs\";\nnamespace greeter;\n" +
+			"rpc_service Greeter { SayHello(hello.Request):hello.Reply; }\n")},
+		"schemas/hello.fbs":        {Data: []byte("include \"types.fbs\";\nnamespace hello;\ntable Request { t:Type; }\ntable Reply {}\n")},
+		"schemas/common/types.fbs": {Data: []byte("namespace hello;\ntable Type {}\n")},
+		"schemas/broken.fbs":       {Data: []byte("include \"../outside.fbs\";\n")},
+	}
+	p := NewFSParser(fsys, "schemas/common")
+	fds, err := p.ParseFiles("./schemas/greeter.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	assert.Equal(t, []string{"hello.fbs", "types.fbs"}, fds[0].Includes)
+	assert.Equal(t, "hello.Request", fds[0].RPCs[0].Methods[0].InputTypeDesc.FullName())
+
+	_, err = p.ParseFiles("schemas/broken.fbs")
+	var notFound *IncludeNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "../outside.fbs", notFound.Name)
+
+	_, err = p.ParseFiles("missing.fbs")
+	assert.True(t, errors.As(err, &notFound))
+}
@@ -0,0 +1,21 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+// Package astinternal gives the parser access to the parts of package ast which are not
+// part of its API.
+package astinternal
+
+// SetTrailingComments sets the trailing comments, a []ast.Comment, of the ast.TerminalNode n.
+// It is used by the lexer, which only knows the comments following a token after the token
+// has been emitted. It is set by package ast.
+var SetTrailingComments func(n interface{}, comments interface{})
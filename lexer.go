@@ -21,7 +21,8 @@ import (
 	"strconv"
 	"strings"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
+	"trpc.group/trpc-go/fbs/internal/astinternal"
 )
 
 // Tabsize is assumed to be 4.
@@ -40,7 +41,7 @@ type fbsLex struct {
 	offset int
 	// presym stores previous token.
 	preSym ast.TerminalNode
-	eof    ast.TerminalNode
+	eof    *ast.RuneNode
 	// line, column and offset of previous token.
 	preLine   int
 	preCol    int
@@ -48,6 +49,11 @@ type fbsLex struct {
 	// comments and ws stores comments and whitespaces.
 	comments []ast.Comment
 	ws       []rune // white space
+	// errToken is set when the last token is an Error, which has already been reported.
+	errToken bool
+	// tok is the last token and context follows the ones before it.
+	tok     int
+	context syntaxContext
 }
 
 // newLexer creates a new lexer that reads in bytes and emits tokens.
@@ -62,22 +68,31 @@ func newLexer(in io.Reader, filename string, errHandler *errorHandler) *fbsLex {
 
 // Lex implements fbsLexer interface defined in fbs.y.go.
 func (f *fbsLex) Lex(lval *fbsSymType) int {
-	if f.handler.getError() != nil {
+	if f.handler.stop(f.handler.getError()) {
 		// error has already occurred, skip the rest of the input
 		return 0
 	}
+	f.errToken = false
 	f.preLine = f.line
 	f.preCol = f.col
 	f.preOffset = f.offset
 	f.comments = nil
 	f.ws = nil
 	f.input.endMark()
-	return f.lex(lval)
+	if f.preSym != nil {
+		f.context.add(f.tok, f.preSym)
+	}
+	f.tok = f.lex(lval)
+	return f.tok
 }
 
 // Error implements fbsLexer interface defined in fbs.y.go.
 func (f *fbsLex) Error(s string) {
-	f.wrappedError(errors.New(s))
+	if f.errToken {
+		// the lexer error is more precise than the syntax error it causes.
+		return
+	}
+	f.wrappedError(f.context.syntaxError(s, f.tok, f.preSym))
 }
 
 // lex start the real processing of the lexer.
@@ -363,19 +378,28 @@ func (f *fbsLex) analyzeBlockComment(lval *fbsSymType, c rune) (cont bool, token
 // analyzeString reads until another quotation mark to form a string literal.
 func (f *fbsLex) analyzeString(quote rune) (string, error) {
 	buf := &bytes.Buffer{}
+	// runeErr is the first invalid rune, the rest of the literal is still consumed
+	// so that lexing can go on after it when all errors are reported.
+	var runeErr error
 	for {
 		c, err := f.readRune()
 		if err != nil {
+			if runeErr != nil {
+				return "", runeErr
+			}
 			return "", err
 		}
 		f.adjustPos(c)
 		if c == quote { // end quote encountered.
 			break
 		}
-		if err := f.analyzeRune(buf, c); err != nil {
-			return "", err
+		if err := f.analyzeRune(buf, c); err != nil && runeErr == nil {
+			runeErr = err
 		}
 	}
+	if runeErr != nil {
+		return "", runeErr
+	}
 	return buf.String(), nil
 }
 
@@ -432,6 +456,7 @@ func (f *fbsLex) setRune(lval *fbsSymType, val rune) {
 
 func (f *fbsLex) setError(lval *fbsSymType, err error) {
 	lval.err = f.wrappedError(err)
+	f.errToken = true
 }
 
 func (f *fbsLex) setInt(lval *fbsSymType, val uint64) {
@@ -507,9 +532,30 @@ func (f *fbsLex) posRange() ast.PosRange {
 
 func (f *fbsLex) newTokenInfo() ast.Token {
 	return ast.Token{
-		PosRange: f.posRange(),
-		RawText:  f.input.endMark(),
+		PosRange:        f.posRange(),
+		RawText:         f.input.endMark(),
+		LeadingComments: f.splitComments(),
+	}
+}
+
+// splitComments attaches the comments starting on the line where the previous token ends to
+// the previous token as trailing comments, and returns the rest as leading comments.
+func (f *fbsLex) splitComments() []ast.Comment {
+	if f.preSym == nil || len(f.comments) == 0 {
+		return f.comments
+	}
+	line := f.preSym.End().Line
+	i := 0
+	for i < len(f.comments) && f.comments[i].Start.Line == line {
+		i++
+	}
+	if i > 0 {
+		astinternal.SetTrailingComments(f.preSym, f.comments[:i:i])
+	}
+	if i == len(f.comments) {
+		return nil
 	}
+	return f.comments[i:]
 }
 
 func (f *fbsLex) newComment() ast.Comment {
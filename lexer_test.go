@@ -20,7 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 func TestLexer(t *testing.T) {
@@ -162,6 +162,49 @@ func TestReadEOF(t *testing.T) {
 	}
 }
 
+func TestLexerComments(t *testing.T) {
+	src := "// leading\n" +
+		"table T { // after brace\n" +
+		"  a: int; /* block */ // line\n" +
+		"  /* before b */ b: int;\n" +
+		"}\n" +
+		"// at end\n"
+	l := newLexer(strings.NewReader(src), "", newErrorHandler())
+	var syms []ast.TerminalNode
+	for {
+		var sym fbsSymType
+		tok := l.Lex(&sym)
+		if tok == 0 {
+			break
+		}
+		switch tok {
+		case Table, Ident, Int:
+			syms = append(syms, sym.id)
+		default:
+			syms = append(syms, sym.r)
+		}
+	}
+	texts := func(comments []ast.Comment) []string {
+		var res []string
+		for _, c := range comments {
+			res = append(res, c.Text)
+		}
+		return res
+	}
+	// table T { a : int ; b : int ; }
+	assert.Equal(t, 12, len(syms))
+	assert.Equal(t, []string{"// leading\n"}, texts(syms[0].LeadingComments()))
+	assert.Equal(t, "", syms[0].LeadingComments()[0].LeadingWhitespace)
+	assert.Equal(t, []string{"// after brace\n"}, texts(syms[2].TrailingComments()))
+	assert.Nil(t, syms[3].LeadingComments())
+	assert.Equal(t, []string{"/* block */", "// line\n"}, texts(syms[6].TrailingComments()))
+	assert.Equal(t, " ", syms[6].TrailingComments()[0].LeadingWhitespace)
+	assert.Equal(t, []string{"/* before b */"}, texts(syms[7].LeadingComments()))
+	assert.Nil(t, syms[7].TrailingComments())
+	assert.Nil(t, syms[11].TrailingComments())
+	assert.Equal(t, []string{"// at end\n"}, texts(l.eof.LeadingComments()))
+}
+
 func TestLexerErrorHandler(t *testing.T) {
 	handler := newErrorHandler()
 	handler.err = errors.New("handler error")
@@ -18,7 +18,7 @@ import (
 	"sort"
 	"strings"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 // sentinelMissingSymbol is used when a symbol cannot be resolved as valid descriptor, but is
@@ -43,16 +43,15 @@ type linker struct {
 	//   "rpc.app.server.MyStruct" => structdesc
 	//   "rpc.app.server.MyStruct.field2" => field2desc
 	//   "rpc.app.server.MyEnum" => enumdesc
-	//   "rpc.app.server.MyEnumVal" => enumvaldesc
+	//   "rpc.app.server.MyEnum.MyEnumVal" => enumvaldesc
 	//   "rpc.app.server.MyUnion" => uniondesc
-	//   "rpc.app.server.MyUnionVal" => unionvaldesc
 	//   "rpc.app.server.MyRPCService" => rpcdesc
 	//   "rpc.app.server.MyRPCService.MyMethod" => methoddesc
 	descPool map[*SchemaDesc]map[string]Desc
 	// packageNamespaces maps schema descriptor to a map that contains a set of namespace strings.
 	// This is used to check whether a string can be resolved as a part of namespace.
 	packageNamespaces map[*SchemaDesc]map[string]struct{}
-	// usedIncludes maps schema descriptor to a map that contains a set of included file names.
+	// usedIncludes maps schema descriptor to a map that contains a set of included file paths.
 	// This is used to check unused includes.
 	usedIncludes map[*SchemaDesc]map[string]struct{}
 }
@@ -72,12 +71,12 @@ func newLinker(results *parseResults, handler *errorHandler) *linker {
 // 2. Resolve type references using the pool created in the prior step.
 func (l *linker) linkFiles() (map[string]*SchemaDesc, error) {
 	// Step1: Put all symbols into a pool. Ensure no duplicates.
-	if err := l.createDescPool(); err != nil {
+	if err := l.createDescPool(); l.handler.stop(err) {
 		return nil, err
 	}
 	// Step2: Try to resolve all type references. They will be re-written
 	// to be fully-qualified references (with leading dot '.' ).
-	if err := l.resolveReferences(); err != nil {
+	if err := l.resolveReferences(); l.handler.stop(err) {
 		return nil, err
 	}
 	// Result of Step2: field type name of tables/structs and input/output type name of rpc methods
@@ -94,10 +93,10 @@ func (l *linker) linkFiles() (map[string]*SchemaDesc, error) {
 func (l *linker) createDescPool() error {
 	l.descPool = map[*SchemaDesc]map[string]Desc{}
 	l.packageNamespaces = map[*SchemaDesc]map[string]struct{}{}
-	if err := l.addDescToPool(); err != nil {
+	if err := l.addDescToPool(); l.handler.stop(err) {
 		return err
 	}
-	if err := l.symbolDuplicated(); err != nil {
+	if err := l.symbolDuplicated(); l.handler.stop(err) {
 		return err
 	}
 	return nil
@@ -112,13 +111,13 @@ func (l *linker) addDescToPool() error {
 		l.descPool[r.fd] = pool
 		// fd.Namespaces example: ["namespace1", "namespace2", "rpc.app.server"]
 		l.packageNamespaces[r.fd] = getAllNamespaces(r.fd.Namespaces)
-		if err := l.addTableStructToPool(r); err != nil {
+		if err := l.addTableStructToPool(r); l.handler.stop(err) {
 			return err
 		}
-		if err := l.addEnumUnionToPool(r); err != nil {
+		if err := l.addEnumUnionToPool(r); l.handler.stop(err) {
 			return err
 		}
-		if err := l.addRPCServiceToPool(r); err != nil {
+		if err := l.addRPCServiceToPool(r); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -129,12 +128,12 @@ func (l *linker) addDescToPool() error {
 // into pool. The same function addTableStruct is used to reduce redundant code.
 func (l *linker) addTableStructToPool(r *parseResult) error {
 	for _, d := range r.fd.Tables {
-		if err := l.addTableStruct(r, d); err != nil {
+		if err := l.addTableStruct(r, d); l.handler.stop(err) {
 			return err
 		}
 	}
 	for _, d := range r.fd.Structs {
-		if err := l.addTableStruct(r, d); err != nil {
+		if err := l.addTableStruct(r, d); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -146,12 +145,12 @@ func (l *linker) addTableStruct(r *parseResult, d TableStructDesc) error {
 	prefix := getPrefix(d) // use its own namespace.
 	// fqn: fully qualified name
 	fqn := prefix + d.GetName() // example: "rpc.app.server.MyTable"
-	if err := l.addToPool(r, fqn, d); err != nil {
+	if err := l.addToPool(r, fqn, d); l.handler.stop(err) {
 		return err
 	}
 	prefix = fqn + "." // example: "rpc.app.server.MyTable."
 	for _, dd := range d.GetFields() {
-		if err := l.addFieldToPool(r, prefix, dd); err != nil {
+		if err := l.addFieldToPool(r, prefix, dd); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -161,12 +160,12 @@ func (l *linker) addTableStruct(r *parseResult, d TableStructDesc) error {
 // addEnumUnionToPool iterates through all enumerations and unions to the pool.
 func (l *linker) addEnumUnionToPool(r *parseResult) error {
 	for _, d := range r.fd.Enums {
-		if err := l.addEnumToPool(r, d); err != nil {
+		if err := l.addEnumToPool(r, d); l.handler.stop(err) {
 			return err
 		}
 	}
 	for _, d := range r.fd.Unions {
-		if err := l.addUnionToPool(r, d); err != nil {
+		if err := l.addUnionToPool(r, d); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -176,7 +175,7 @@ func (l *linker) addEnumUnionToPool(r *parseResult) error {
 // addRPCServiceToPool add all rpc service definitions into the pool.
 func (l *linker) addRPCServiceToPool(r *parseResult) error {
 	for _, d := range r.fd.RPCs {
-		if err := l.addRPCToPool(r, d); err != nil {
+		if err := l.addRPCToPool(r, d); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -187,13 +186,13 @@ func (l *linker) addRPCServiceToPool(r *parseResult) error {
 func (l *linker) addEnumToPool(r *parseResult, d *EnumDesc) error {
 	prefix := getPrefix(d) // use its own namespace.
 	fqn := prefix + d.Name // example: "rpc.app.server.MyEnum"
-	if err := l.addToPool(r, fqn, d); err != nil {
+	if err := l.addToPool(r, fqn, d); l.handler.stop(err) {
 		return err
 	}
 	for _, dd := range d.Values {
-		// enum value type name is in the same scope as the enum name.
-		vfqn := prefix + dd.Name // example: "rpc.app.server.MyEnumValueName"
-		if err := l.addToPool(r, vfqn, dd); err != nil {
+		// enum values are scoped under the enum, so that different enums may share value names.
+		vfqn := fqn + "." + dd.Name // example: "rpc.app.server.MyEnum.MyEnumValueName"
+		if err := l.addToPool(r, vfqn, dd); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -204,7 +203,7 @@ func (l *linker) addEnumToPool(r *parseResult, d *EnumDesc) error {
 func (l *linker) addUnionToPool(r *parseResult, d *UnionDesc) error {
 	prefix := getPrefix(d) // use its own namespace.
 	fqn := prefix + d.Name // example: "rpc.app.server.MyUnion"
-	if err := l.addToPool(r, fqn, d); err != nil {
+	if err := l.addToPool(r, fqn, d); l.handler.stop(err) {
 		return err
 	}
 	return nil
@@ -215,12 +214,12 @@ func (l *linker) addUnionToPool(r *parseResult, d *UnionDesc) error {
 func (l *linker) addRPCToPool(r *parseResult, d *RPCDesc) error {
 	prefix := getPrefix(d) // use its own namespace.
 	fqn := prefix + d.Name // example: "rpc.app.server.MyRPCService"
-	if err := l.addToPool(r, fqn, d); err != nil {
+	if err := l.addToPool(r, fqn, d); l.handler.stop(err) {
 		return err
 	}
 	for _, dd := range d.Methods {
 		mfqn := fqn + "." + dd.Name // example: "rpc.app.server.MyRPCService.MyMethod"
-		if err := l.addToPool(r, mfqn, dd); err != nil {
+		if err := l.addToPool(r, mfqn, dd); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -237,8 +236,8 @@ func (l *linker) addFieldToPool(r *parseResult, prefix string, d *FieldDesc) err
 func (l *linker) addToPool(r *parseResult, fqn string, dsc Desc) error {
 	if d, ok := l.descPool[r.fd][fqn]; ok { // ok means duplicate!
 		node := r.descToNode[dsc]
-		if err := l.handler.handleErrorWithPos(node.Start(),
-			"duplicate symbol %s: already defined as %s", fqn, descType(d)); err != nil {
+		dup := &DuplicateSymbolError{Name: fqn, Kind: descType(d), Previous: r.descToNode[d].Start()}
+		if err := l.handler.handleError(ErrorWithPos{Pos: node.Start(), End: node.End(), Err: dup}); err != nil {
 			return err
 		}
 	}
@@ -266,9 +265,12 @@ func (l *linker) symbolDuplicated() error {
 		for _, k := range keys {
 			v := p[k]
 			if e, ok := pool[k]; ok {
-				return l.errDuplicateSymbol(k, &e, &entry{fd.Name, v})
+				if err := l.errDuplicateSymbol(k, &e, &entry{fd.Path, v}); l.handler.stop(err) {
+					return err
+				}
+				continue
 			}
-			pool[k] = entry{file: fd.Name, dsc: v}
+			pool[k] = entry{file: fd.Path, dsc: v}
 		}
 	}
 	return nil
@@ -290,8 +292,13 @@ func (l *linker) errDuplicateSymbol(s string, e1, e2 *entry) error {
 		e1, e2 = e2, e1
 	}
 	node := l.files[e2.file].descToNode[e2.dsc]
-	return l.handler.handleErrorWithPos(node.Start(),
-		"duplicate symbol %s: already defined as %s in %q", s, descType(e1.dsc), e1.file)
+	err := &DuplicateSymbolError{
+		Name:     s,
+		Kind:     descType(e1.dsc),
+		File:     l.files[e1.file].fd.Name,
+		Previous: l.files[e1.file].descToNode[e1.dsc].Start(),
+	}
+	return l.handler.handleError(ErrorWithPos{Pos: node.Start(), End: node.End(), Err: err})
 }
 
 // resolveReferences resolves type references using type definitions stored in the pool.
@@ -301,13 +308,96 @@ func (l *linker) resolveReferences() error {
 		r := l.files[filename]
 		fd := r.fd
 		scopes := []scope{schemaScope(fd, l)}
-		if err := l.resolveTypeReferences(r, scopes); err != nil {
+		if err := l.resolveTypeReferences(r, scopes); l.handler.stop(err) {
 			return err
 		}
+		for _, d := range fd.Unions {
+			if err := l.resolveUnion(r, d, scopes); l.handler.stop(err) {
+				return err
+			}
+		}
 		for _, d := range fd.RPCs {
-			if err := l.resolveRPCs(r, d, scopes); err != nil {
+			if err := l.resolveRPCs(r, d, scopes); l.handler.stop(err) {
+				return err
+			}
+		}
+		if err := l.resolveRoot(r, scopes); l.handler.stop(err) {
+			return err
+		}
+		// checkShadowedNames looks up symbols as well, so includes must be checked first.
+		if err := l.checkUnusedIncludes(r); l.handler.stop(err) {
+			return err
+		}
+		if err := l.checkShadowedNames(r); l.handler.stop(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUnusedIncludes warns about the included files none of whose symbols is used, either
+// directly or through the files they include. Files declaring attributes are skipped.
+func (l *linker) checkUnusedIncludes(r *parseResult) error {
+	used := l.usedIncludes[r.fd]
+	for _, incl := range r.getSchemaNode(r.fd).Includes {
+		name := incl.Name.Val
+		res := l.files[r.includes[name]]
+		if res == nil || len(res.fd.Attrs) > 0 {
+			continue // not parsed, see Parser.Recursive.
+		}
+		if _, ok := used[res.fd.Path]; ok {
+			continue
+		}
+		if err := l.handler.handleWarning(WarnUnusedInclude, incl.Name,
+			"include %q is not used", name); l.handler.stop(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkShadowedNames warns about the types hiding a type of the same name declared in an
+// enclosing namespace, as references from the enclosing namespace resolve to the other one.
+// Example:
+//
+//	namespace a;
+//	table T {}
+//	namespace a.b;
+//	table T {} // a.b.T shadows a.T
+func (l *linker) checkShadowedNames(r *parseResult) error {
+	var descs []NamedDesc
+	for _, d := range r.fd.Tables {
+		descs = append(descs, d)
+	}
+	for _, d := range r.fd.Structs {
+		descs = append(descs, d)
+	}
+	for _, d := range r.fd.Enums {
+		descs = append(descs, d)
+	}
+	for _, d := range r.fd.Unions {
+		descs = append(descs, d)
+	}
+	for _, d := range descs {
+		ns := d.(NamespaceDesc).GetNamespace()
+		name := strings.TrimPrefix(d.FullName(), ns+".")
+		for ns != "" {
+			fqn := name
+			if i := strings.LastIndexByte(ns, '.'); i >= 0 {
+				ns = ns[:i]
+				fqn = ns + "." + name
+			} else {
+				ns = ""
+			}
+			other := l.findSymbol(r.fd, fqn)
+			if other == nil || other == sentinelMissingSymbol || !isType(other) || other == Desc(d) {
+				continue
+			}
+			if err := l.handler.handleWarning(WarnShadowedName, r.getNode(d),
+				"%s %s shadows %s %s", descType(d), d.FullName(), descType(other), fqn); l.handler.stop(err) {
 				return err
 			}
+			break
 		}
 	}
 	return nil
@@ -319,12 +409,12 @@ func (l *linker) resolveReferences() error {
 //	                    ^^^^ This is going to be resolved.
 func (l *linker) resolveTypeReferences(r *parseResult, scopes []scope) error {
 	for _, d := range r.fd.Tables {
-		if err := l.resolveTableStruct(r, d, scopes); err != nil {
+		if err := l.resolveTableStruct(r, d, scopes); l.handler.stop(err) {
 			return err
 		}
 	}
 	for _, d := range r.fd.Structs {
-		if err := l.resolveTableStruct(r, d, scopes); err != nil {
+		if err := l.resolveTableStruct(r, d, scopes); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -336,14 +426,231 @@ func (l *linker) resolveTableStruct(r *parseResult, d TableStructDesc, scopes []
 	prefix := getPrefix(d)
 	fqn := prefix + d.GetName() // example: "rpc.app.server.MyTable"
 	prefix = fqn + "."          // example: "rpc.app.server.MyTable."
+	var failed error
 	for _, dd := range d.GetFields() {
 		if err := l.resolveFields(r, prefix, dd, scopes); err != nil {
+			if l.handler.stop(err) {
+				return err
+			}
+			failed = err
+		}
+	}
+	if failed != nil {
+		// attributes can not be checked against unresolved types.
+		return failed
+	}
+	return l.resolveFieldAttributes(r, d, prefix, scopes)
+}
+
+// resolveFieldAttributes checks the well-known attributes of fields against their resolved
+// types, resolves nested_flatbuffer and assigns field ids. Examples:
+//
+//	table Monster { name:string (required, key); }
+//	                             ^^^^^^^^^^^^^ checked against type string.
+//
+//	table Monster { testnestedflatbuffer:[ubyte] (nested_flatbuffer: "Monster"); }
+//	                                                                 ^^^^^^^^^
+//	                                               resolved to be the table Monster.
+func (l *linker) resolveFieldAttributes(r *parseResult, d TableStructDesc, prefix string, scopes []scope) error {
+	_, isStruct := d.(*StructDesc)
+	var key *FieldDesc
+	var failed error
+	for _, dd := range d.GetFields() {
+		if err := l.resolveFieldAttribute(r, dd, isStruct, key, prefix, scopes); err != nil {
+			if l.handler.stop(err) {
+				return err
+			}
+			failed = err
+		}
+		if dd.IsKey && key == nil {
+			key = dd
+		}
+	}
+	if err := l.assignFieldIDs(r, d, prefix); err != nil {
+		return err
+	}
+	return failed
+}
+
+// resolveFieldAttribute checks the well-known attributes of a single field, key is the field
+// already set as key, if any.
+func (l *linker) resolveFieldAttribute(r *parseResult, d *FieldDesc, isStruct bool, key *FieldDesc,
+	prefix string, scopes []scope) error {
+	scope := fmt.Sprintf("field %s", prefix+d.Name)
+	node := r.getFieldNode(d)
+	isScalar := d.Type.BaseType.IsScalar()
+	if d.IsRequired && (isStruct || isScalar) {
+		return l.handler.handleErrorWithNode(node.Metadata.Entry(attrRequired),
+			"%s: only non-scalar fields in tables may be required", scope)
+	}
+	if d.IsDeprecated && isStruct {
+		return l.handler.handleErrorWithNode(node.Metadata.Entry(attrDeprecated),
+			"%s: fields of structs can not be deprecated", scope)
+	}
+	if d.IsOptional && isStruct {
+		return l.handler.handleErrorWithNode(node.Scalar,
+			"%s: optional scalars are not supported in structs", scope)
+	}
+	if d.IsKey {
+		if !isScalar && d.Type.BaseType != BaseTypeString {
+			return l.handler.handleErrorWithNode(node.Metadata.Entry(attrKey),
+				"%s: only scalar or string fields may be set as key", scope)
+		}
+		if key != nil {
+			return l.handler.handleErrorWithNode(node.Metadata.Entry(attrKey),
+				"%s: only one field may be set as key, %s is already the key", scope, key.Name)
+		}
+	}
+	if name, ok := d.Metadata.GetString(attrNestedFlatbuffer); ok {
+		return l.resolveNestedFlatbuffer(r, d, name, scope, scopes)
+	}
+	return nil
+}
+
+// resolveNestedFlatbuffer resolves the root table given by the nested_flatbuffer attribute.
+func (l *linker) resolveNestedFlatbuffer(r *parseResult, d *FieldDesc, name, scope string, scopes []scope) error {
+	node := r.getFieldNode(d).Metadata.Entry(attrNestedFlatbuffer).Value
+	fqn, dsc := l.resolve(r.fd, name, scopes)
+	if dsc == nil || dsc == sentinelMissingSymbol {
+		return l.errUnknownType(r.fd, node, scope, "nested flatbuffer type", name, "", "table")
+	}
+	td, ok := dsc.(*TableDesc)
+	if !ok {
+		return l.errInvalidType(node, scope, "nested flatbuffer type", fqn, dsc, "table")
+	}
+	d.NestedFlatbuffer = td
+	return nil
+}
+
+// assignFieldIDs assigns ids to fields in declaration order. If any field is given an id by
+// the id attribute, all fields must have one and the ids must be contiguous from 0. A union
+// field takes two ids, the first one is used by its type field. Example:
+//
+//	table T { a:int (id: 1); u:Any (id: 3); b:int (id: 0); }
+//	// a: 1, u_type: 2, u: 3, b: 0
+func (l *linker) assignFieldIDs(r *parseResult, d TableStructDesc, prefix string) error {
+	fields := d.GetFields()
+	var explicit bool
+	for _, dd := range fields {
+		explicit = explicit || dd.Metadata.Has(attrID)
+	}
+	if !explicit {
+		var id int
+		for _, dd := range fields {
+			if isUnionField(dd) {
+				id++
+			}
+			dd.ID = id
+			id++
+		}
+		return nil
+	}
+	owners := make(map[int]*FieldDesc)
+	for _, dd := range fields {
+		scope := fmt.Sprintf("field %s", prefix+dd.Name)
+		node := r.getFieldNode(dd)
+		e := node.Metadata.Entry(attrID)
+		if e == nil {
+			return l.handler.handleErrorWithNode(node,
+				"%s: either all fields or no fields must have an id attribute", scope)
+		}
+		ids := []int{dd.ID}
+		if isUnionField(dd) {
+			if dd.ID == 0 {
+				return l.handler.handleErrorWithNode(e.Value,
+					"%s: union field id must be at least 1, its type field uses id-1", scope)
+			}
+			ids = append(ids, dd.ID-1)
+		}
+		for _, id := range ids {
+			if other, ok := owners[id]; ok {
+				return l.handler.handleErrorWithNode(e.Value,
+					"%s: id %d is already used by field %s", scope, id, other.Name)
+			}
+			owners[id] = dd
+		}
+	}
+	for id := 0; id < len(owners); id++ {
+		if _, ok := owners[id]; !ok {
+			return l.handler.handleErrorWithNode(r.getNode(d),
+				"%s %s: field ids must be contiguous from 0, id %d is missing",
+				descType(d), strings.TrimSuffix(prefix, "."), id)
+		}
+	}
+	return nil
+}
+
+// resolveUnion resolves the member types of a union. Members must be tables, structs or
+// strings. Example:
+//
+//	union Any { Monster, M2: MyGame.Example2.Monster }
+//	            ^^^^^^^      ^^^^^^^^^^^^^^^^^^^^^^^ These are going to be resolved.
+func (l *linker) resolveUnion(r *parseResult, d *UnionDesc, scopes []scope) error {
+	scope := fmt.Sprintf("union %s", getPrefix(d)+d.Name)
+	for _, dd := range d.Values {
+		if err := l.resolveUnionVal(r, dd, scope, scopes); l.handler.stop(err) {
 			return err
 		}
 	}
 	return nil
 }
 
+// resolveUnionVal resolves the type of a single union member.
+func (l *linker) resolveUnionVal(r *parseResult, d *UnionValDesc, scope string, scopes []scope) error {
+	node := r.getUnionValNode(d)
+	if node.TypeName.OpenBracket != nil {
+		return l.handler.handleErrorWithNode(node.TypeName,
+			"%s: invalid member type: vector is not allowed", scope)
+	}
+	if _, ok := keywords[d.TypeName]; ok {
+		if LookupBaseType(d.TypeName) != BaseTypeString {
+			return l.handler.handleErrorWithNode(node.TypeName,
+				"%s: invalid member type %s, must be a table, struct or string", scope, d.TypeName)
+		}
+		return nil
+	}
+	fqn, dsc := l.resolve(r.fd, d.TypeName, scopes)
+	if dsc == nil || dsc == sentinelMissingSymbol {
+		return l.errUnknownType(r.fd, node.TypeName, scope, "type", d.TypeName, fqn,
+			"table", "struct", "string")
+	}
+	switch dsc.(type) {
+	case *TableDesc, *StructDesc:
+		d.TypeName = "." + fqn // Transform d.TypeName to be fully qualified.
+		d.TypeDesc = dsc
+	default:
+		return l.errInvalidType(node.TypeName, scope, "member type", fqn, dsc, "table", "struct", "string")
+	}
+	return nil
+}
+
+// resolveRoot resolves the root_type declaration which must be a table. Example:
+//
+//	root_type MyGame.Example.Monster;
+//	          ^^^^^^^^^^^^^^^^^^^^^^ This is going to be resolved.
+func (l *linker) resolveRoot(r *parseResult, scopes []scope) error {
+	fd := r.fd
+	if fd.Root == "" {
+		return nil
+	}
+	var node *ast.RootDeclNode
+	for _, decl := range r.getSchemaNode(fd).Decls {
+		if n, ok := decl.(*ast.RootDeclNode); ok {
+			node = n // The last root_type declaration takes effect.
+		}
+	}
+	fqn, dsc := l.resolve(fd, fd.Root, scopes)
+	if dsc == nil || dsc == sentinelMissingSymbol {
+		return l.errUnknownType(fd, node.Name, "root_type", "type", fd.Root, fqn, "table")
+	}
+	d, ok := dsc.(*TableDesc)
+	if !ok {
+		return l.errInvalidType(node.Name, "root_type", "type", fqn, dsc, "table")
+	}
+	fd.RootDesc = d
+	return nil
+}
+
 // ReqRspType provides an interface for Request(input) and Response(output) types.
 type ReqRspType interface {
 	MethodName() string
@@ -353,8 +660,16 @@ type ReqRspType interface {
 	StartPosition() *ast.Position
 }
 
-var _ ReqRspType = (*ReqType)(nil)
-var _ ReqRspType = (*RspType)(nil)
+// reqRspNode is a ReqRspType which also gives what the type is used as and its syntax node,
+// so that errors can tell requests from responses and cover the whole type name.
+type reqRspNode interface {
+	ReqRspType
+	Role() string
+	Node() ast.Node
+}
+
+var _ reqRspNode = (*ReqType)(nil)
+var _ reqRspNode = (*RspType)(nil)
 
 // ReqType implements ReqRspType, representing request type.
 type ReqType struct {
@@ -367,6 +682,11 @@ func (r *ReqType) MethodName() string {
 	return r.dd.Name
 }
 
+// Role returns what the type is used as, see UnknownTypeError.Role.
+func (r *ReqType) Role() string {
+	return "request type"
+}
+
 // TypeName implements interface ReqRspType.
 func (r *ReqType) TypeName() string {
 	return r.dd.InputType
@@ -384,7 +704,12 @@ func (r *ReqType) SetTypeDesc(d *TableDesc) {
 
 // StartPosition implements interface ReqRspType.
 func (r *ReqType) StartPosition() *ast.Position {
-	return r.r.getMethodNode(r.dd).ReqName.Start()
+	return r.Node().Start()
+}
+
+// Node returns the type name node of the method.
+func (r *ReqType) Node() ast.Node {
+	return r.r.getMethodNode(r.dd).ReqName
 }
 
 // RspType implements ReqRspType, representing response type.
@@ -398,6 +723,11 @@ func (r *RspType) MethodName() string {
 	return r.dd.Name
 }
 
+// Role returns what the type is used as, see UnknownTypeError.Role.
+func (r *RspType) Role() string {
+	return "response type"
+}
+
 // TypeName implements interface ReqRspType.
 func (r *RspType) TypeName() string {
 	return r.dd.OutputType
@@ -415,7 +745,12 @@ func (r *RspType) SetTypeDesc(d *TableDesc) {
 
 // StartPosition implements interface ReqRspType.
 func (r *RspType) StartPosition() *ast.Position {
-	return r.r.getMethodNode(r.dd).RspName.Start()
+	return r.Node().Start()
+}
+
+// Node returns the type name node of the method.
+func (r *RspType) Node() ast.Node {
+	return r.r.getMethodNode(r.dd).RspName
 }
 
 // resolveRPCs resolves type references used in methods' input/output. Examples:
@@ -429,11 +764,11 @@ func (l *linker) resolveRPCs(r *parseResult, d *RPCDesc, scopes []scope) error {
 	rpcServiceName := prefix + d.Name
 	for _, dd := range d.Methods {
 		// resolve request type
-		if err := l.resolveReqRsp(&ReqType{r, dd}, r, scopes, rpcServiceName); err != nil {
+		if err := l.resolveReqRsp(&ReqType{r, dd}, r, scopes, rpcServiceName); l.handler.stop(err) {
 			return err
 		}
 		// resolve response type
-		if err := l.resolveReqRsp(&RspType{r, dd}, r, scopes, rpcServiceName); err != nil {
+		if err := l.resolveReqRsp(&RspType{r, dd}, r, scopes, rpcServiceName); l.handler.stop(err) {
 			return err
 		}
 	}
@@ -442,23 +777,15 @@ func (l *linker) resolveRPCs(r *parseResult, d *RPCDesc, scopes []scope) error {
 
 // resolveReqRsp resolves either input(request) type or output(response) type. This function use interface to
 // avoid duplicate code.
-func (l *linker) resolveReqRsp(rt ReqRspType, r *parseResult, scopes []scope, rpcServiceName string) error {
+func (l *linker) resolveReqRsp(rt reqRspNode, r *parseResult, scopes []scope, rpcServiceName string) error {
 	scope := fmt.Sprintf("method %s.%s", rpcServiceName, rt.MethodName())
 	fqn, dsc := l.resolve(r.fd, rt.TypeName(), scopes)
-	if dsc == nil {
-		return l.handler.handleErrorWithPos(rt.StartPosition(), "%s: unknown response type %s",
-			scope, rt.TypeName())
-	}
-	if dsc == sentinelMissingSymbol {
-		return l.handler.handleErrorWithPos(rt.StartPosition(),
-			"%s: unknown response type %s; resolved to %s which is not defined",
-			scope, rt.TypeName(), fqn)
+	if dsc == nil || dsc == sentinelMissingSymbol {
+		return l.errUnknownType(r.fd, rt.Node(), scope, rt.Role(), rt.TypeName(), fqn, "table")
 	}
 	d, ok := dsc.(*TableDesc)
 	if !ok {
-		otherType := descType(dsc)
-		return l.handler.handleErrorWithPos(rt.StartPosition(),
-			"%s: invalid response type: %s is a %s, not a table", scope, fqn, otherType)
+		return l.errInvalidType(rt.Node(), scope, rt.Role(), fqn, dsc, "table")
 	}
 	rt.SetTypeName("." + fqn)
 	rt.SetTypeDesc(d)
@@ -485,6 +812,7 @@ func (l *linker) resolveReqRsp(rt ReqRspType, r *parseResult, scopes []scope, rp
 //	// `linker.descPool`, therefore it can be successfully resolved.
 func (l *linker) resolveFields(r *parseResult, prefix string, d *FieldDesc, scopes []scope) error {
 	if _, ok := keywords[d.TypeName]; ok {
+		d.Type = newFieldType(d.IsVector, LookupBaseType(d.TypeName))
 		return nil
 	}
 	thisName := prefix + d.Name // example: "rpc.app.server.MyTable.MyFieldName"
@@ -492,24 +820,135 @@ func (l *linker) resolveFields(r *parseResult, prefix string, d *FieldDesc, scop
 	node := r.getFieldNode(d)
 	// d.TypeName example: "namespace2.MyFieldTypeName"
 	fqn, dsc := l.resolve(r.fd, d.TypeName, scopes)
-	if dsc == nil {
-		return l.handler.handleErrorWithPos(node.Start(), "%s: unknown type %s", scope, d.TypeName)
-	}
-	if dsc == sentinelMissingSymbol {
-		return l.handler.handleErrorWithPos(node.Start(),
-			"%s: unknown type %s; resolved to %s which is not defined", scope, d.TypeName, fqn)
+	if dsc == nil || dsc == sentinelMissingSymbol {
+		return l.errUnknownType(r.fd, node, scope, "type", d.TypeName, fqn)
 	}
 	switch dsc := dsc.(type) {
-	case *TableDesc, *StructDesc, *EnumDesc, *UnionDesc:
+	case *EnumDesc:
 		d.TypeName = "." + fqn // Transform d.TypeName to be fully qualified.
+		d.TypeDesc = dsc
+		d.Type = newFieldType(d.IsVector, dsc.BaseType)
+		return l.resolveEnumDefault(node, d, dsc, scope)
+	case *TableDesc, *StructDesc, *UnionDesc:
+		d.TypeName = "." + fqn // Transform d.TypeName to be fully qualified.
+		d.TypeDesc = dsc
+		d.Type = newFieldType(d.IsVector, kindOf(dsc))
+		if node.Scalar != nil {
+			return l.handler.handleErrorWithNode(node.Scalar,
+				"%s: default value is only allowed for scalar fields", scope)
+		}
 	default:
-		otherType := descType(dsc)
-		return l.handler.handleErrorWithPos(node.Start(), "%s: invalid type: %s is a %s",
-			scope, fqn, otherType)
+		return l.errInvalidType(node, scope, "type", fqn, dsc)
 	}
 	return nil
 }
 
+// errUnknownType reports a type name, referred to by node, which can not be resolved from fd.
+// fqn is the name it resolved to if it is a namespace rather than a type, empty otherwise.
+// Only the types of the allowed kinds are suggested, any type if none is given.
+func (l *linker) errUnknownType(fd *SchemaDesc, node ast.Node, scope, role, name, fqn string,
+	allowed ...string) error {
+	err := &UnknownTypeError{
+		Scope:       scope,
+		Role:        role,
+		Name:        name,
+		Resolved:    fqn,
+		Suggestions: l.suggest(fd, name, allowed),
+	}
+	return l.handler.handleError(ErrorWithPos{Pos: node.Start(), End: node.End(), Err: err})
+}
+
+// errInvalidType reports a type name, referred to by node, resolved to dsc whose kind is not allowed.
+func (l *linker) errInvalidType(node ast.Node, scope, role, fqn string, dsc Desc, allowed ...string) error {
+	err := &InvalidTypeError{
+		Scope:      scope,
+		Role:       role,
+		Name:       fqn,
+		Kind:       descType(dsc),
+		Allowed:    allowed,
+		Definition: l.position(dsc),
+	}
+	return l.handler.handleError(ErrorWithPos{Pos: node.Start(), End: node.End(), Err: err})
+}
+
+// position returns the start of the definition of d, which may be in any of the files.
+func (l *linker) position(d Desc) *ast.Position {
+	for _, name := range l.filenames {
+		if n := l.files[name].getNode(d); n != nil {
+			return n.Start()
+		}
+	}
+	return nil
+}
+
+// isUnionField reports whether the type of the field is a union or a vector of union.
+func isUnionField(d *FieldDesc) bool {
+	return d.Type.BaseType == BaseTypeUnion || d.Type.ElementType == BaseTypeUnion
+}
+
+// kindOf returns the base type of fields whose type is the given table, struct or union.
+func kindOf(d Desc) BaseType {
+	switch d.(type) {
+	case *TableDesc:
+		return BaseTypeTable
+	case *StructDesc:
+		return BaseTypeStruct
+	case *UnionDesc:
+		return BaseTypeUnion
+	}
+	return BaseTypeNone
+}
+
+// resolveEnumDefault resolves the default value of a field whose type is enum. Examples:
+//
+//	table Monster { color : Color = Blue; }
+//	                                ^^^^ resolved to be the enum value Blue of Color.
+//
+//	table Monster { color : Color = 8; }
+//	                                ^ resolved to be the enum value of Color whose number is 8.
+func (l *linker) resolveEnumDefault(node *ast.FieldNode, d *FieldDesc, ed *EnumDesc, scope string) error {
+	if node.Scalar == nil || d.DefaultIsNull {
+		return nil
+	}
+	if d.IsVector {
+		return l.handler.handleErrorWithNode(node.Scalar,
+			"%s: default value is only allowed for scalar fields", scope)
+	}
+	switch v := node.Scalar.(type) {
+	case *ast.IdentNode:
+		for _, ev := range ed.Values {
+			if ev.Name == v.Val {
+				d.Default = ev
+				return nil
+			}
+		}
+		return l.handler.handleErrorWithNode(v,
+			"%s: default value %s is not a value of enum %s", scope, v.Val, ed.Name)
+	case ast.IntValueNode:
+		n, err := enumDefault(ed, v)
+		if err != nil {
+			return l.handler.handleErrorWithNode(v,
+				"%s: default value of enum %s: %w", scope, ed.Name, err)
+		}
+		d.Default = n
+		bitFlags := ed.Metadata.Has(attrBitFlags)
+		for _, ev := range ed.Values {
+			num := ev.Number
+			if bitFlags {
+				num = int64(1) << uint(ev.Number)
+			}
+			if num == n {
+				d.Default = ev
+				break
+			}
+		}
+		return nil
+	default:
+		return l.handler.handleErrorWithNode(v,
+			"%s: default value %v is not a valid value of enum %s", scope, v.Value(), ed.Name)
+	}
+}
+
 // resolve resolves `name` to be a predefined descriptor (either defined in this file scope or in
 // the included files' scope). Typically, name is the type name used in fields in table/struct or methods
 // in rpc declarations. Examples:
@@ -590,7 +1029,7 @@ func (l *linker) findSymbolRecursive(entryPoint, fd *SchemaDesc, name string,
 func (l *linker) findSymbolFromIncludes(entryPoint, fd *SchemaDesc, name string,
 	checked map[*SchemaDesc]struct{}) Desc {
 	for _, incl := range fd.Includes {
-		res := l.files[incl]
+		res := l.include(fd, incl)
 		if res == nil {
 			continue
 		}
@@ -623,5 +1062,14 @@ func (l *linker) markUsed(entryPoint, used *SchemaDesc) {
 		includesForFile = map[string]struct{}{}
 		l.usedIncludes[entryPoint] = includesForFile
 	}
-	includesForFile[used.Name] = struct{}{}
+	includesForFile[used.Path] = struct{}{}
+}
+
+// include returns the parse result of the file included as name by fd, nil if it is not parsed.
+func (l *linker) include(fd *SchemaDesc, name string) *parseResult {
+	r := l.files[fd.Path]
+	if r == nil {
+		return nil
+	}
+	return l.files[r.includes[name]]
 }
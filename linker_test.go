@@ -16,6 +16,8 @@ package fbs
 import (
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestLinker(t *testing.T) {
@@ -151,6 +153,24 @@ func TestLinker(t *testing.T) {
 			wantLen:   0,
 			wantErr:   true,
 		},
+		{
+			name:      "field default value is not a value of enum",
+			filenames: []string{"./fbsfiles/error_test/link_test21.fbs"},
+			wantLen:   0,
+			wantErr:   true,
+		},
+		{
+			name:      "field default value given for table type",
+			filenames: []string{"./fbsfiles/error_test/link_test22.fbs"},
+			wantLen:   0,
+			wantErr:   true,
+		},
+		{
+			name:      "same enum value name in different enums",
+			filenames: []string{"./fbsfiles/enum_value_scope_test.fbs"},
+			wantLen:   1,
+			wantErr:   false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -167,6 +187,61 @@ func TestLinker(t *testing.T) {
 	}
 }
 
+func TestEnumValueScope(t *testing.T) {
+	p := NewParser()
+	fds, err := p.ParseFiles("./fbsfiles/enum_value_scope_test.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	fd := fds[0]
+	color, light := fd.Enums[0], fd.Enums[1]
+	palette := fd.Tables[1]
+	assert.Equal(t, "Palette", palette.Name)
+	assert.Equal(t, color.Values[0], palette.Fields[0].Default)
+	assert.Equal(t, light.Values[2], palette.Fields[1].Default)
+	assert.Equal(t, int64(3), light.Values[2].Number)
+}
+
+func TestFullNameMatchesDescPool(t *testing.T) {
+	p := NewParser()
+	_, l, err := p.link([]string{"./fbsfiles/monster_test.fbs"})
+	assert.Nil(t, err)
+	var n int
+	for _, pool := range l.descPool {
+		for fqn, d := range pool {
+			nd, ok := d.(NamedDesc)
+			assert.True(t, ok)
+			assert.Equal(t, fqn, nd.FullName())
+			n++
+		}
+	}
+	assert.NotZero(t, n)
+}
+
+func TestParents(t *testing.T) {
+	p := NewParser()
+	fds, err := p.ParseFiles("./fbsfiles/simple_test1.fbs")
+	assert.Nil(t, err)
+	fd := fds[0]
+	table := fd.Tables[0]
+	assert.Equal(t, fd, table.Schema)
+	assert.Equal(t, table, table.Fields[0].Parent)
+	assert.Equal(t, "mynamespace.MyTable1.myfield1", table.Fields[0].FullName())
+	enum := fd.Enums[0]
+	assert.Equal(t, fd, enum.Schema)
+	assert.Equal(t, enum, enum.Values[1].Parent)
+	assert.Equal(t, "mynamespace.MyEnum.Green", enum.Values[1].FullName())
+	union := fd.Unions[0]
+	assert.Equal(t, fd, union.Schema)
+	assert.Equal(t, union, union.Values[0].Parent)
+	assert.Equal(t, "mynamespace.Any.MyTable1", union.Values[0].FullName())
+	assert.Equal(t, fd, fd.Structs[0].Schema)
+	assert.Equal(t, "mynamespace.MyEmptyStruct", fd.Structs[0].FullName())
+	rpc := fd.RPCs[0]
+	assert.Equal(t, fd, rpc.Schema)
+	assert.Equal(t, rpc, rpc.Methods[0].Parent)
+	assert.Equal(t, "mynamespace.MyService1.MyMethod1", rpc.Methods[0].FullName())
+}
+
 func TestGetFullNamespaces(t *testing.T) {
 	type args struct {
 		nss []string
@@ -14,9 +14,10 @@
 package fbs
 
 import (
-	"math"
+	"fmt"
+	"strings"
 
-	"trpc.group/trpc-go/fbs/internal/ast"
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 // String literals for streaming option used in metadata fields of rpc methods.
@@ -27,16 +28,16 @@ const (
 	BidiStreaming   = "bidi"
 )
 
-// parseResults stores all parsed results index by their original filename.
+// parseResults stores all parsed results index by their path, see SchemaDesc.Path.
 type parseResults struct {
-	// resultsByFilename maps filename to *parseResult, Example:
+	// resultsByFilename maps path to *parseResult, Example:
 	//
-	//  "monster_test.fbs" => parseres1
-	//  "dir1/file1.fbs" => parseres2
+	//  "/schemas/monster_test.fbs" => parseres1
+	//  "/schemas/dir1/file1.fbs" => parseres2
 	resultsByFilename map[string]*parseResult
 	// filenames stores keys in resultsByFilename, in parsing order. Example:
 	//
-	//  ["monster_test.fbs", "dir1/file1.fbs"]
+	//  ["/schemas/monster_test.fbs", "/schemas/dir1/file1.fbs"]
 	filenames []string
 	// recursive decides whether to parse included files recursively.
 	recursive bool
@@ -66,6 +67,10 @@ type parseResult struct {
 	// this is typically used to extract position
 	// information when validating the descriptor.
 	descToNode map[Desc]ast.Node
+	// includes maps the included file names, as written, to their path. Example:
+	//
+	//  "../common/base.fbs" => "/schemas/common/base.fbs"
+	includes map[string]string
 }
 
 // newParseResult creates a new parse result out of schema node.
@@ -74,6 +79,7 @@ func newParseResult(filename string, schema *ast.SchemaNode, handler *errorHandl
 		root:       schema,
 		handler:    handler,
 		descToNode: map[Desc]ast.Node{},
+		includes:   map[string]string{},
 	}
 	if createFbs {
 		res.createSchemaDescriptor(filename, schema)
@@ -87,6 +93,7 @@ func (p *parseResult) createSchemaDescriptor(filename string, schema *ast.Schema
 		Schema:     schema,
 		Name:       filename,
 		Namespaces: []string{""},
+		nodes:      p.descToNode,
 	}
 	p.fd = fd
 	p.putSchemaNode(fd, schema)
@@ -111,8 +118,16 @@ func (p *parseResult) createSchemaDescriptor(filename string, schema *ast.Schema
 			fd.Root = string(decl.Name.Identifier())
 		case *ast.FileExtDeclNode:
 			fd.FileExt = decl.Name.Val
+			if fd.FileExt == "" || strings.HasPrefix(fd.FileExt, ".") {
+				_ = p.handler.handleErrorWithNode(decl.Name,
+					"file_extension %q must be non-empty and must not start with a dot", fd.FileExt)
+			}
 		case *ast.FileIdentDeclNode:
 			fd.FileIdent = decl.Name.Val
+			if len(fd.FileIdent) != fileIdentLength {
+				_ = p.handler.handleErrorWithNode(decl.Name,
+					"file_identifier %q must be exactly %d bytes", fd.FileIdent, fileIdentLength)
+			}
 		case *ast.AttrDeclNode:
 			fd.Attrs = append(fd.Attrs, decl.Name.Val)
 		default:
@@ -123,9 +138,11 @@ func (p *parseResult) createSchemaDescriptor(filename string, schema *ast.Schema
 
 func (p *parseResult) asTableDesc(n *ast.TableDeclNode) *TableDesc {
 	d := &TableDesc{
-		Schema:    p.fd,
-		Namespace: p.fd.Namespaces[len(p.fd.Namespaces)-1],
-		Name:      n.Name.Val,
+		Schema:        p.fd,
+		Namespace:     p.fd.Namespaces[len(p.fd.Namespaces)-1],
+		Name:          n.Name.Val,
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
 	p.putTableNode(d, n)
 	p.addTableFields(d, n.Fields)
@@ -134,8 +151,11 @@ func (p *parseResult) asTableDesc(n *ast.TableDeclNode) *TableDesc {
 
 func (p *parseResult) asStructDesc(n *ast.StructDeclNode) *StructDesc {
 	d := &StructDesc{
-		Namespace: p.fd.Namespaces[len(p.fd.Namespaces)-1],
-		Name:      n.Name.Val,
+		Schema:        p.fd,
+		Namespace:     p.fd.Namespaces[len(p.fd.Namespaces)-1],
+		Name:          n.Name.Val,
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
 	p.putStructNode(d, n)
 	p.addStructFields(d, n.Fields)
@@ -144,46 +164,168 @@ func (p *parseResult) asStructDesc(n *ast.StructDeclNode) *StructDesc {
 
 func (p *parseResult) asEnumDesc(n *ast.EnumDeclNode) *EnumDesc {
 	d := &EnumDesc{
-		Namespace: p.fd.Namespaces[len(p.fd.Namespaces)-1],
-		Name:      n.Name.Val,
+		Schema:        p.fd,
+		Namespace:     p.fd.Namespaces[len(p.fd.Namespaces)-1],
+		Name:          n.Name.Val,
+		TypeName:      string(n.TypeName.TypeName.Identifier()),
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
+	}
+	d.BaseType = LookupBaseType(d.TypeName)
+	if n.TypeName.OpenBracket != nil || !d.BaseType.IsInteger() {
+		_ = p.handler.handleErrorWithNode(n.TypeName,
+			"enum %s: underlying type must be an integral type", d.Name)
+		d.BaseType = BaseTypeNone
 	}
 	p.putEnumNode(d, n)
-	enumNum := int32(0)
-	// Append backwards, since the constructed AST is in reverse order.
-	for i := len(n.Decls) - 1; i >= 0; i-- {
-		decl := n.Decls[i]
-		d.Values = append(d.Values, p.asEnumVal(decl, &enumNum))
+	var prev *EnumValDesc
+	for _, decl := range n.Decls {
+		prev = p.asEnumVal(d, decl, prev)
+		d.Values = append(d.Values, prev)
 	}
 	return d
 }
 
-func (p *parseResult) asEnumVal(n *ast.EnumValueNode, enumNum *int32) *EnumValDesc {
-	if n.IntVal != nil {
-		var ok bool
-		*enumNum, ok = ast.AsInt32(n.IntVal, math.MinInt32, math.MaxInt32)
-		if !ok {
-			_ = p.handler.handleErrorWithPos(n.IntVal.Start(), "value %d is out of range: [%d,%d]",
-				n.IntVal.Value(), math.MinInt32, math.MaxInt32)
-		}
-	}
+// attrBitFlags is the attribute of enums whose values are bit positions.
+const attrBitFlags = "bit_flags"
+
+// asEnumVal creates the descriptor of an enum value. The number of the value is either given
+// explicitly or one more than the previous value, it is checked against the range of the
+// underlying type of the enum. Example:
+//
+//	enum Color:ubyte { Red = 254, Green, Blue }
+//	                                     ^^^^ value 256 is out of range of ubyte.
+func (p *parseResult) asEnumVal(ed *EnumDesc, n *ast.EnumValueNode, prev *EnumValDesc) *EnumValDesc {
 	d := &EnumValDesc{
-		Name:   n.Name.Val,
-		Number: *enumNum,
+		Parent:        ed,
+		Name:          n.Name.Val,
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
-	*enumNum++
 	p.putEnumValNode(d, n)
+	switch {
+	case n.IntVal != nil:
+		v, err := enumNumber(ed, n.IntVal)
+		if err != nil {
+			_ = p.handler.handleErrorWithNode(n.IntVal, "enum %s: %w", ed.Name, err)
+		} else if prev != nil && !enumLess(ed, prev.Number, v) {
+			_ = p.handler.handleWarning(WarnEnumOrder, n.IntVal,
+				"enum %s: value %s = %v is not greater than the previous value %s = %v",
+				ed.Name, d.Name, enumValue(ed, v), prev.Name, enumValue(ed, prev.Number))
+		}
+		d.Number = v
+	case prev != nil:
+		if ed.BaseType != BaseTypeNone && prev.Number == enumMaxNumber(ed) {
+			_ = p.handler.handleErrorWithNode(n,
+				"enum %s: implicit value of %s overflows %s", ed.Name, d.Name, ed.BaseType)
+		}
+		d.Number = prev.Number + 1
+	}
 	return d
 }
 
+// enumLess reports whether enum number a is less than b, according to the signedness of the
+// underlying type of the enum.
+func enumLess(ed *EnumDesc, a, b int64) bool {
+	if ed.BaseType.IsUnsigned() {
+		return uint64(a) < uint64(b)
+	}
+	return a < b
+}
+
+// enumValue returns enum number v as it is written, unsigned for unsigned underlying types.
+func enumValue(ed *EnumDesc, v int64) interface{} {
+	if ed.BaseType.IsUnsigned() {
+		return uint64(v)
+	}
+	return v
+}
+
+// enumNumber converts an explicit enum value into its number according to the underlying
+// type of the enum. Values of bit_flags enums are bit positions.
+func enumNumber(ed *EnumDesc, n ast.IntValueNode) (int64, error) {
+	t := ed.BaseType
+	switch {
+	case t == BaseTypeNone:
+		v, _ := n.AsInt64()
+		return v, nil
+	case ed.Metadata.Has(attrBitFlags):
+		bits := uint64(t.Size() * 8)
+		if v, ok := n.AsUint64(); ok && v < bits {
+			return int64(v), nil
+		}
+		return 0, &RangeError{What: "bit flag", Value: n.Value(), Min: 0, Max: bits - 1}
+	case t.IsUnsigned():
+		v, err := uintValue(t, n)
+		if err != nil {
+			return 0, err
+		}
+		return int64(v.(uint64)), nil
+	default:
+		v, err := intValue(t, n)
+		if err != nil {
+			return 0, err
+		}
+		return v.(int64), nil
+	}
+}
+
+// enumDefault converts an integer default value of a field whose type is the given enum.
+// Default values of bit_flags enums are values rather than bit positions. Example:
+//
+//	color:Color = 2;
+//	              ^ This is going to be converted.
+func enumDefault(ed *EnumDesc, n ast.IntValueNode) (int64, error) {
+	v, err := scalarValue(ed.BaseType, n)
+	if err != nil {
+		return 0, err
+	}
+	if u, ok := v.(uint64); ok {
+		return int64(u), nil
+	}
+	return v.(int64), nil
+}
+
+// enumMaxNumber returns the maximum number of values of the given enum. The maximum of ulong
+// is represented as -1, see EnumValDesc.Number.
+func enumMaxNumber(ed *EnumDesc) int64 {
+	t := ed.BaseType
+	switch {
+	case ed.Metadata.Has(attrBitFlags):
+		return int64(t.Size()*8 - 1)
+	case t.IsUnsigned():
+		return int64(t.uintMax())
+	default:
+		_, mx := t.intRange()
+		return mx
+	}
+}
+
 func (p *parseResult) asUnionDesc(n *ast.UnionDeclNode) *UnionDesc {
 	d := &UnionDesc{
-		Namespace: p.fd.Namespaces[len(p.fd.Namespaces)-1],
-		Name:      n.Name.Val,
+		Schema:        p.fd,
+		Namespace:     p.fd.Namespaces[len(p.fd.Namespaces)-1],
+		Name:          n.Name.Val,
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
 	p.putUnionNode(d, n)
-	for i := len(n.Decls) - 1; i >= 0; i-- {
-		decl := n.Decls[i]
-		d.Values = append(d.Values, p.asUnionVal(decl))
+	members := make(map[string]struct{})
+	for _, decl := range n.Decls {
+		dd := p.asUnionVal(decl)
+		dd.Parent = d
+		// Discriminants start from 1, 0 is reserved for the implicit NONE member.
+		dd.Number = int64(len(d.Values) + 1)
+		member := dd.member
+		if _, ok := members[member]; ok || member == UnionNone {
+			_ = p.handler.handleErrorWithNode(decl, "union %s: duplicate member %s", d.Name, member)
+		}
+		if dd.Number > int64(BaseTypeUbyte.uintMax()) {
+			_ = p.handler.handleErrorWithNode(decl, "union %s: too many members, at most %d are allowed",
+				d.Name, BaseTypeUbyte.uintMax())
+		}
+		members[member] = struct{}{}
+		d.Values = append(d.Values, dd)
 	}
 	return d
 }
@@ -193,19 +335,32 @@ func (p *parseResult) asUnionVal(n *ast.UnionValueNode) *UnionValDesc {
 	if n.Name != nil {
 		name = n.Name.Val
 	}
-	d := &UnionValDesc{Name: name, TypeName: string(n.TypeName.TypeName.Identifier())}
+	d := &UnionValDesc{
+		Name:          name,
+		TypeName:      string(n.TypeName.TypeName.Identifier()),
+		Documentation: documentation(n),
+	}
+	d.member = d.Name
+	if d.member == "" {
+		d.member = d.TypeName
+	}
 	p.putUnionValNode(d, n)
 	return d
 }
 
 func (p *parseResult) asRPCDesc(n *ast.RPCDeclNode) *RPCDesc {
 	d := &RPCDesc{
-		Namespace: p.fd.Namespaces[len(p.fd.Namespaces)-1],
-		Name:      n.Name.Val,
+		Schema:        p.fd,
+		Namespace:     p.fd.Namespaces[len(p.fd.Namespaces)-1],
+		Name:          n.Name.Val,
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
 	p.putRPCNode(d, n)
 	for _, decl := range n.Methods {
-		d.Methods = append(d.Methods, p.asMethodDesc(decl))
+		dd := p.asMethodDesc(decl)
+		dd.Parent = d
+		d.Methods = append(d.Methods, dd)
 	}
 	return d
 }
@@ -223,10 +378,11 @@ func setStreaming(d *MethodDesc, v interface{}) {
 
 func (p *parseResult) asMethodDesc(n *ast.RPCMethodNode) *MethodDesc {
 	d := &MethodDesc{
-		Name:       n.Name.Val,
-		InputType:  string(n.ReqName.Identifier()),
-		OutputType: string(n.RspName.Identifier()),
-		Metadata:   p.asMetadataDesc(n.Metadata),
+		Name:          n.Name.Val,
+		InputType:     string(n.ReqName.Identifier()),
+		OutputType:    string(n.RspName.Identifier()),
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
 	if d.Metadata != nil {
 		v, ok := d.Metadata.KV[Streaming]
@@ -243,7 +399,11 @@ func (p *parseResult) asMetadataDesc(n *ast.MetadataNode) *MetadataDesc {
 		return nil
 	}
 	d := &MetadataDesc{KV: map[string]interface{}{}}
+	p.putMetadataNode(d, n)
 	for _, entry := range n.Entries {
+		if _, ok := d.KV[entry.Key.Val]; !ok {
+			d.Keys = append(d.Keys, entry.Key.Val)
+		}
 		if entry.Value == nil {
 			d.KV[entry.Key.Val] = nil
 		} else {
@@ -253,25 +413,267 @@ func (p *parseResult) asMetadataDesc(n *ast.MetadataNode) *MetadataDesc {
 	return d
 }
 
-func (p *parseResult) asFieldDesc(n *ast.FieldNode) *FieldDesc {
+func (p *parseResult) asFieldDesc(parent TableStructDesc, n *ast.FieldNode) *FieldDesc {
 	d := &FieldDesc{
-		Name:     n.Name.Val,
-		TypeName: string(n.TypeName.TypeName.Identifier()),
-		IsVector: n.TypeName.OpenBracket != nil && n.TypeName.CloseBracket != nil,
+		Parent:        parent,
+		Name:          n.Name.Val,
+		TypeName:      string(n.TypeName.TypeName.Identifier()),
+		IsVector:      n.TypeName.OpenBracket != nil && n.TypeName.CloseBracket != nil,
+		Metadata:      p.asMetadataDesc(n.Metadata),
+		Documentation: documentation(n),
 	}
 	p.putFieldNode(d, n)
+	p.setFieldDefault(d, n)
+	p.setFieldAttributes(d, n)
 	return d
 }
 
+// docPrefix is the prefix of documentation comments.
+const docPrefix = "///"
+
+// documentation returns the lines of documentation comments before the given node, with
+// docPrefix removed. Ordinary comments are ignored, as well as the ones starting with more
+// than three slashes, such as separator lines, like flatc does. Example:
+//
+//	/// an example documentation comment: "monster object"
+//	table Monster {}
+//	// documentation: [` an example documentation comment: "monster object"`]
+func documentation(n ast.Node) []string {
+	t := ast.FirstToken(n)
+	if t == nil {
+		return nil
+	}
+	var docs []string
+	for _, c := range t.LeadingComments() {
+		if strings.HasPrefix(c.Text, docPrefix) && !strings.HasPrefix(c.Text, docPrefix+"/") {
+			docs = append(docs, strings.TrimRight(c.Text[len(docPrefix):], "\r\n"))
+		}
+	}
+	return docs
+}
+
+// fileIdentLength is the length of file_identifier in bytes.
+const fileIdentLength = 4
+
+// nullDefault is the identifier used to declare an optional scalar field.
+const nullDefault = "null"
+
+// setFieldDefault sets the default value of fields whose type is builtin. Default values of
+// fields with enum types are resolved by the linker. Example:
+//
+//	hp:short = 100;
+//	           ^^^ This is going to be set.
+func (p *parseResult) setFieldDefault(d *FieldDesc, n *ast.FieldNode) {
+	if n.Scalar == nil {
+		return
+	}
+	if id, ok := n.Scalar.(*ast.IdentNode); ok && id.Val == nullDefault {
+		d.DefaultIsNull = true
+	}
+	t := LookupBaseType(d.TypeName)
+	if t == BaseTypeNone {
+		return // Not a builtin type, leave it to the linker.
+	}
+	if d.IsVector || !t.IsScalar() {
+		_ = p.handler.handleErrorWithNode(n.Scalar,
+			"field %s: default value is only allowed for scalar fields", d.FullName())
+		return
+	}
+	if d.DefaultIsNull {
+		return
+	}
+	v, err := scalarValue(t, n.Scalar)
+	if err != nil {
+		_ = p.handler.handleErrorWithNode(n.Scalar, "field %s: %w", d.FullName(), err)
+		return
+	}
+	d.Default = v
+}
+
+// Well-known attributes of fields.
+const (
+	attrID               = "id"
+	attrRequired         = "required"
+	attrDeprecated       = "deprecated"
+	attrKey              = "key"
+	attrHash             = "hash"
+	attrNestedFlatbuffer = "nested_flatbuffer"
+)
+
+// hashAlgorithms maps the supported hash algorithms to their widths in bits.
+var hashAlgorithms = map[string]int{
+	"fnv1_16":  16,
+	"fnv1a_16": 16,
+	"fnv1_32":  32,
+	"fnv1a_32": 32,
+	"fnv1_64":  64,
+	"fnv1a_64": 64,
+}
+
+// setFieldAttributes interprets the well-known attributes of a field. Checks that depend on
+// resolved types, such as required and key, are done by the linker. Example:
+//
+//	id:ulong (id: 2, key, hash:"fnv1a_64");
+//	          ^^^^^^^^^^^^^^^^^^^^^^^^^^^ These are going to be set.
+func (p *parseResult) setFieldAttributes(d *FieldDesc, n *ast.FieldNode) {
+	d.IsOptional = d.DefaultIsNull
+	d.IsRequired = d.Metadata.Has(attrRequired)
+	d.IsDeprecated = d.Metadata.Has(attrDeprecated)
+	d.IsKey = d.Metadata.Has(attrKey)
+	if e := n.Metadata.Entry(attrID); e != nil {
+		p.setFieldID(d, e)
+	}
+	if e := n.Metadata.Entry(attrHash); e != nil {
+		p.setFieldHash(d, e)
+	}
+	if e := n.Metadata.Entry(attrNestedFlatbuffer); e != nil {
+		p.checkNestedFlatbuffer(d, e)
+	}
+	if d.IsDeprecated {
+		p.checkDeprecatedField(d, n)
+	}
+}
+
+// checkDeprecatedField warns about a deprecated field which is still in use. Example:
+//
+//	name:string (deprecated, required);
+//	                         ^^^^^^^^ This is going to be reported.
+func (p *parseResult) checkDeprecatedField(d *FieldDesc, n *ast.FieldNode) {
+	for _, attr := range []string{attrRequired, attrKey} {
+		if e := n.Metadata.Entry(attr); e != nil {
+			_ = p.handler.handleWarning(WarnDeprecatedField, e,
+				"field %s: deprecated field is still marked %s", d.FullName(), attr)
+		}
+	}
+}
+
+func (p *parseResult) setFieldID(d *FieldDesc, e *ast.MetadataEntryNode) {
+	if e.Value == nil {
+		_ = p.handler.handleErrorWithNode(e, "field %s: attribute id requires a value", d.FullName())
+		return
+	}
+	v, err := uintValue(BaseTypeUshort, e.Value)
+	if err != nil {
+		_ = p.handler.handleErrorWithNode(e.Value, "field %s: attribute id: %w", d.FullName(), err)
+		return
+	}
+	d.ID = int(v.(uint64))
+}
+
+func (p *parseResult) setFieldHash(d *FieldDesc, e *ast.MetadataEntryNode) {
+	name, ok := d.Metadata.GetString(attrHash)
+	if !ok {
+		_ = p.handler.handleErrorWithNode(e, "field %s: attribute hash requires a string value", d.FullName())
+		return
+	}
+	bits, ok := hashAlgorithms[name]
+	if !ok {
+		_ = p.handler.handleErrorWithNode(e.Value, "field %s: unknown hash algorithm %s", d.FullName(), name)
+		return
+	}
+	t := LookupBaseType(d.TypeName)
+	if !t.IsInteger() || t.Size() < 2 {
+		_ = p.handler.handleErrorWithNode(e,
+			"field %s: only short, ushort, int, uint, long and ulong fields support hashing", d.FullName())
+		return
+	}
+	if t.Size()*8 != bits {
+		_ = p.handler.handleErrorWithNode(e.Value,
+			"field %s: hash algorithm %s does not match the %d-bit type %s", d.FullName(), name, t.Size()*8, t)
+		return
+	}
+	d.Hash = name
+}
+
+func (p *parseResult) checkNestedFlatbuffer(d *FieldDesc, e *ast.MetadataEntryNode) {
+	if _, ok := d.Metadata.GetString(attrNestedFlatbuffer); !ok {
+		_ = p.handler.handleErrorWithNode(e,
+			"field %s: attribute nested_flatbuffer requires a string value", d.FullName())
+		return
+	}
+	if !d.IsVector || LookupBaseType(d.TypeName) != BaseTypeUbyte {
+		_ = p.handler.handleErrorWithNode(e,
+			"field %s: nested_flatbuffer attribute may only apply to a vector of ubyte", d.FullName())
+	}
+}
+
+// scalarValue converts a value node into a value of the given scalar type.
+func scalarValue(t BaseType, n ast.ValueNode) (interface{}, error) {
+	switch {
+	case t == BaseTypeBool:
+		return boolValue(n)
+	case t.IsFloat():
+		return floatValue(n)
+	case t.IsUnsigned():
+		return uintValue(t, n)
+	default:
+		return intValue(t, n)
+	}
+}
+
+func boolValue(n ast.ValueNode) (interface{}, error) {
+	switch n := n.(type) {
+	case *ast.BoolLiteralNode:
+		return n.Val, nil
+	case ast.IntValueNode:
+		if v, ok := n.AsUint64(); ok && v <= 1 {
+			return v == 1, nil
+		}
+	}
+	return nil, fmt.Errorf("value %v is not a valid bool", n.Value())
+}
+
+func floatValue(n ast.ValueNode) (interface{}, error) {
+	switch n := n.(type) {
+	case ast.FloatValueNode:
+		return n.AsFloat(), nil
+	case ast.IntValueNode:
+		if v, ok := n.AsInt64(); ok {
+			return float64(v), nil
+		}
+		v, _ := n.AsUint64()
+		return float64(v), nil
+	}
+	return nil, fmt.Errorf("value %v is not a valid floating point", n.Value())
+}
+
+func uintValue(t BaseType, n ast.ValueNode) (interface{}, error) {
+	in, ok := n.(ast.IntValueNode)
+	if !ok {
+		return nil, fmt.Errorf("value %v is not a valid %s", n.Value(), t)
+	}
+	mx := t.uintMax()
+	v, ok := in.AsUint64()
+	if !ok || v > mx {
+		return nil, &RangeError{What: "value", Value: in.Value(), Min: 0, Max: mx}
+	}
+	return v, nil
+}
+
+func intValue(t BaseType, n ast.ValueNode) (interface{}, error) {
+	in, ok := n.(ast.IntValueNode)
+	if !ok {
+		return nil, fmt.Errorf("value %v is not a valid %s", n.Value(), t)
+	}
+	mi, mx := t.intRange()
+	v, ok := in.AsInt64()
+	if !ok || v < mi || v > mx {
+		return nil, &RangeError{What: "value", Value: in.Value(), Min: mi, Max: mx}
+	}
+	return v, nil
+}
+
 func (p *parseResult) addTableFields(d *TableDesc, fields []*ast.FieldNode) {
 	for _, field := range fields {
-		d.Fields = append(d.Fields, p.asFieldDesc(field))
+		dd := p.asFieldDesc(d, field)
+		d.Fields = append(d.Fields, dd)
 	}
 }
 
 func (p *parseResult) addStructFields(d *StructDesc, fields []*ast.FieldNode) {
 	for _, field := range fields {
-		d.Fields = append(d.Fields, p.asFieldDesc(field))
+		dd := p.asFieldDesc(d, field)
+		d.Fields = append(d.Fields, dd)
 	}
 }
 
@@ -311,10 +713,21 @@ func (p *parseResult) putMethodNode(d *MethodDesc, n *ast.RPCMethodNode) {
 	p.descToNode[d] = n
 }
 
+func (p *parseResult) putMetadataNode(d *MetadataDesc, n *ast.MetadataNode) {
+	p.descToNode[d] = n
+}
+
 func (p *parseResult) putFieldNode(d *FieldDesc, n *ast.FieldNode) {
 	p.descToNode[d] = n
 }
 
+func (p *parseResult) getNode(d Desc) ast.Node {
+	if p.descToNode == nil {
+		return nil
+	}
+	return p.descToNode[d]
+}
+
 func (p *parseResult) getSchemaNode(d *SchemaDesc) *ast.SchemaNode {
 	if p.descToNode == nil {
 		return nil
@@ -329,6 +742,13 @@ func (p *parseResult) getFieldNode(d *FieldDesc) *ast.FieldNode {
 	return p.descToNode[d].(*ast.FieldNode)
 }
 
+func (p *parseResult) getUnionValNode(d *UnionValDesc) *ast.UnionValueNode {
+	if p.descToNode == nil {
+		return nil
+	}
+	return p.descToNode[d].(*ast.UnionValueNode)
+}
+
 func (p *parseResult) getMethodNode(d *MethodDesc) *ast.RPCMethodNode {
 	if p.descToNode == nil {
 		return nil
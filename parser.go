@@ -14,11 +14,13 @@
 package fbs
 
 import (
-	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"trpc.group/trpc-go/fbs/ast"
 )
 
 //go:generate goyacc -o fbs.y.go -p fbs fbs.y
@@ -26,7 +28,10 @@ import (
 // Parser parses .fbs source into descriptors.
 type Parser struct {
 	accessor  FileAccessor
+	paths     []string // include paths searched, see extendPaths.
 	filenames []string
+	keys      []string          // keys of filenames in results, see SchemaDesc.Path.
+	opened    map[string]string // paths the files in results were opened at, by key.
 	results   *parseResults
 	handler   *errorHandler
 	// IncludePaths stores paths used to search for dependencies
@@ -38,6 +43,23 @@ type Parser struct {
 	// Recursive decides whether to parse the file recursively (parse includes).
 	// default is true.
 	Recursive bool
+	// Accessor, if set, opens the files to parse, both the given and the included ones,
+	// instead of the file system. Each include path is tried in turn as a prefix of the
+	// file names, as for the file system. Default is nil.
+	Accessor FileAccessor
+	// AllErrors decides whether to report all errors instead of only the first one.
+	// If set, parsing and linking go on after an error, and the error returned is an
+	// ErrorList sorted by position. Files with syntax errors, and the files including
+	// them, are not linked. Default is false.
+	AllErrors bool
+	// Reporter, if set, is given all errors and warnings. Parsing goes on after an error
+	// unless the reporter returns an error, see Reporter. Default is nil.
+	Reporter Reporter
+	// WarningsAsErrors decides whether warnings are reported as errors. Default is false.
+	WarningsAsErrors bool
+	// failed stores the files that failed to be read or had syntax errors, by path, as well as
+	// the files including them.
+	failed map[string]bool
 }
 
 // NewParser creates a parser.
@@ -53,10 +75,45 @@ func (p *Parser) SetRecursive(recursive bool) {
 	p.Recursive = recursive
 }
 
+// SetAccessor configures how the files to parse are opened.
+func (p *Parser) SetAccessor(accessor FileAccessor) {
+	p.Accessor = accessor
+}
+
+// SetAllErrors configures whether parser reports all errors instead of only the first one.
+func (p *Parser) SetAllErrors(allErrors bool) {
+	p.AllErrors = allErrors
+}
+
+// SetReporter configures the reporter given all errors and warnings.
+func (p *Parser) SetReporter(reporter Reporter) {
+	p.Reporter = reporter
+}
+
+// SetWarningsAsErrors configures whether warnings are reported as errors.
+func (p *Parser) SetWarningsAsErrors(warningsAsErrors bool) {
+	p.WarningsAsErrors = warningsAsErrors
+}
+
 // ParseFiles parse a list of .fbs files into descriptors.
 func (p *Parser) ParseFiles(filenames ...string) ([]*SchemaDesc, error) {
-	paths := extendPaths(p.IncludePaths, filenames)
-	p.accessor = getAccessor(paths)
+	linkedFbs, _, err := p.link(filenames)
+	if err != nil {
+		return nil, err
+	}
+	fds := make([]*SchemaDesc, len(filenames))
+	for i := range filenames {
+		fd := linkedFbs[p.keys[i]]
+		fds[i] = fd
+	}
+	return fds, nil
+}
+
+// link parses and links the files. It returns the linked descriptors by path, see
+// SchemaDesc.Path, and the linker.
+func (p *Parser) link(filenames []string) (map[string]*SchemaDesc, *linker, error) {
+	p.paths = extendPaths(p.IncludePaths, filenames)
+	p.accessor = getAccessor(p.Accessor)
 	p.filenames = filenames
 	fbs := map[string]*parseResult{}
 	p.results = &parseResults{
@@ -65,79 +122,211 @@ func (p *Parser) ParseFiles(filenames ...string) ([]*SchemaDesc, error) {
 		createDescriptorFbs: true,
 	}
 	p.handler = newErrorHandler()
+	p.handler.collect = p.AllErrors || p.Reporter != nil
+	p.handler.reporter = p.Reporter
+	p.handler.warningsAsErrors = p.WarningsAsErrors
+	p.failed = map[string]bool{}
+	p.opened = map[string]string{}
 	// Step1: source files => descriptors.
 	// including lexing and parsing.
-	if err := p.parseFiles(); err != nil {
-		return nil, err
+	if err := p.parseFiles(); p.handler.stop(err) {
+		return nil, nil, err
 	}
+	p.excludeFailed()
 	// Note: if recursive is set, here results will not only contain the ones specified in
 	// `filenames`, but also the files that are included by them.
 	// Step2: link all parsed descriptors.
 	l := newLinker(p.results, p.handler)
 	linkedFbs, err := l.linkFiles()
-	if err != nil {
-		return nil, err
+	if err := p.handler.result(); err != nil {
+		return nil, nil, err
 	}
-	fds := make([]*SchemaDesc, len(filenames))
-	for i, name := range filenames {
-		fd := linkedFbs[name]
-		fds[i] = fd
+	if err != nil {
+		return nil, nil, err
 	}
-	return fds, nil
+	return linkedFbs, l, nil
 }
 
 // parseFiles iterates all the given files to generate parse results.
 func (p *Parser) parseFiles() error {
-	for _, name := range p.filenames {
-		p.parseFile(name)
-		if p.handler.getError() != nil {
+	p.keys = make([]string, len(p.filenames))
+	for i, name := range p.filenames {
+		p.keys[i] = p.parseFile(name, "", nil)
+		if p.handler.stop(p.handler.getError()) {
 			return p.handler.err
 		}
 	}
-	return nil
+	if !p.Recursive {
+		p.resolveIncludes()
+	}
+	return p.handler.getError()
 }
 
-// parseFile parses a single file. If Parser.Recursive is set, it will iterate all
-// its includes to parse recursively.
-func (p *Parser) parseFile(filename string) {
-	result, ok := p.parse(filename)
-	if !ok {
+// resolveIncludes maps the includes of the parsed files to the files given to the parser,
+// as they are not parsed when Parser.Recursive is not set. Includes that are not given are
+// left unresolved.
+func (p *Parser) resolveIncludes() {
+	for _, key := range p.results.filenames {
+		result := p.results.resultsByFilename[key]
+		for _, incl := range result.getSchemaNode(result.fd).Includes {
+			in, path, err := p.open(incl.Name.Val, p.opened[key])
+			if err != nil {
+				continue
+			}
+			_ = in.Close()
+			if inclKey := p.canonical(path); p.results.has(inclKey) || p.failed[inclKey] {
+				result.includes[incl.Name.Val] = inclKey
+			}
+		}
+	}
+}
+
+// excludeFailed removes from the parse results the files that failed, as well as
+// the files including them directly or indirectly, so that only files without
+// errors get linked. Their errors have already been reported.
+func (p *Parser) excludeFailed() {
+	if len(p.failed) == 0 {
 		return
 	}
+	for changed := true; changed; {
+		changed = false
+		for _, name := range p.results.filenames {
+			if p.failed[name] {
+				continue
+			}
+			for _, incl := range p.results.resultsByFilename[name].includes {
+				if p.failed[incl] {
+					p.failed[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	filenames := p.results.filenames[:0]
+	for _, name := range p.results.filenames {
+		if p.failed[name] {
+			delete(p.results.resultsByFilename, name)
+			continue
+		}
+		filenames = append(filenames, name)
+	}
+	p.results.filenames = filenames
+}
+
+// parseFile parses a single file, given as filename or included as filename by the file
+// opened at from. If Parser.Recursive is set, it will iterate all its includes to parse
+// recursively. pos is the position of the include statement, if any. It returns the key of
+// the file in the parse results, empty if the file could not be read.
+func (p *Parser) parseFile(filename, from string, pos *ast.Position) string {
+	result, path, key, ok := p.parse(filename, from, pos)
+	if !ok {
+		return key
+	}
 	if p.Recursive {
 		fd := result.fd
 		schema := result.getSchemaNode(fd)
 		// Even if the file is empty, schema would be nil.
 		for _, incl := range schema.Includes {
-			p.parseFile(incl.Name.Val)
-			if p.handler.getError() != nil {
-				return
+			inclKey := p.parseFile(incl.Name.Val, path, incl.Name.Start())
+			if p.handler.stop(p.handler.getError()) {
+				return key
+			}
+			if inclKey == "" {
+				p.failed[key] = true
+				continue
+			}
+			result.includes[incl.Name.Val] = inclKey
+			if p.failed[inclKey] {
+				continue
 			}
-			result := p.results.resultsByFilename[incl.Name.Val]
-			fd.Dependencies = append(fd.Dependencies, result.fd)
+			fd.Dependencies = append(fd.Dependencies, p.results.resultsByFilename[inclKey].fd)
 		}
 	}
+	return key
 }
 
-// parse does the actual parsing.
-func (p *Parser) parse(filename string) (*parseResult, bool) {
-	if p.results.has(filename) {
-		return nil, false
-	}
-	in, err := p.accessor(filename)
+// parse does the actual parsing. It returns the parse result, the path the file was opened at
+// and its key in the parse results, see Parser.canonical.
+func (p *Parser) parse(filename, from string, pos *ast.Position) (*parseResult, string, string, bool) {
+	in, path, err := p.open(filename, from)
 	if err != nil {
-		_ = p.handler.handleError(err)
-		return nil, false
+		if pos != nil {
+			err = ErrorWithPos{Pos: pos, Err: err}
+			_ = p.handler.handleError(err)
+		} else {
+			// a given file is reported as is, and under its name among all errors.
+			_ = p.handler.handle(err, ErrorWithPos{Pos: &ast.Position{Filename: filename}, Err: err})
+		}
+		return nil, "", "", false
 	}
+	key := p.canonical(path)
+	if p.results.has(key) || p.failed[key] {
+		_ = in.Close()
+		return nil, path, key, false
+	}
+	errs := len(p.handler.errs)
 	l := newLexer(in, filename, p.handler)
 	fbsParse(l)
+	// only syntax errors make the file unusable, the others are reported while linking it.
+	syntaxErrors := len(p.handler.errs) > errs
+	if l.res == nil {
+		// nothing could be recovered from the syntax errors.
+		l.res = ast.NewSchemaNode(nil, nil)
+	}
+	l.res.EOF = l.eof
 	result := newParseResult(filename, l.res, l.handler, p.results.createDescriptorFbs)
+	result.fd.Path = key
+	p.opened[key] = path
 	_ = in.Close()
-	p.results.add(filename, result)
-	if p.handler.getError() != nil {
-		return nil, false
+	p.results.add(key, result)
+	if p.handler.stop(p.handler.getError()) {
+		return nil, path, key, false
+	}
+	if syntaxErrors {
+		// keep going with the includes so that their errors are reported too.
+		p.failed[key] = true
+	}
+	return result, path, key, true
+}
+
+// open opens the file given as filename, or included as filename by the file opened at from.
+// Like flatc, an included file is looked up next to the including file first, then in the
+// include paths. It returns the path the file is opened at.
+func (p *Parser) open(filename, from string) (io.ReadCloser, string, error) {
+	paths := p.paths
+	if from != "" {
+		paths = append([]string{filepath.Dir(from)}, paths...)
+	}
+	return openFile(p.accessor, paths, filename)
+}
+
+// canonical returns the path identifying the file opened at path, see SchemaDesc.Path.
+func (p *Parser) canonical(path string) string {
+	if p.Accessor == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
 	}
-	return result, true
+	return filepath.Clean(path)
+}
+
+// ParseSchema parses the source of a single .fbs file into its syntax tree. Includes are not
+// followed and no descriptors are built, so only syntax errors are reported. It is meant for
+// tools working on the source text, such as formatters.
+func ParseSchema(filename string, r io.Reader) (*ast.SchemaNode, error) {
+	handler := newErrorHandler()
+	l := newLexer(r, filename, handler)
+	fbsParse(l)
+	if err := handler.getError(); err != nil {
+		return nil, err
+	}
+	schema := l.res
+	if schema == nil {
+		schema = ast.NewSchemaNode(nil, nil)
+	}
+	schema.EOF = l.eof
+	return schema, nil
 }
 
 // extendPaths add necessary paths to current include paths, for example:
@@ -164,27 +353,62 @@ func extendPaths(paths []string, filenames []string) []string {
 	return paths
 }
 
-// getAccessor will create an file accessor that will try every include path as prefix
-// to correctly open a file.
-func getAccessor(paths []string) func(name string) (io.ReadCloser, error) {
-	accessor := func(name string) (io.ReadCloser, error) {
+// getAccessor returns open, or an accessor opening the files with os.Open if open is nil.
+func getAccessor(open FileAccessor) FileAccessor {
+	if open != nil {
+		return open
+	}
+	return func(name string) (io.ReadCloser, error) {
 		return os.Open(name)
 	}
-	if len(paths) > 0 {
-		acc := accessor
-		accessor = func(name string) (io.ReadCloser, error) {
-			for _, path := range paths {
-				f, err := acc(filepath.Join(path, name))
-				if err != nil {
-					continue
-				}
-				return f, nil
+}
+
+// openFile tries every path in paths as prefix to open the named file with open. It returns
+// the path the file is opened at. If the file can not be found, the first error other than a
+// missing file is returned, if any.
+func openFile(open FileAccessor, paths []string, name string) (io.ReadCloser, string, error) {
+	var firstErr error
+	for _, path := range paths {
+		joined := filepath.Join(path, name)
+		f, err := open(joined)
+		if err != nil {
+			if firstErr == nil && !os.IsNotExist(err) {
+				firstErr = err
 			}
-			return nil, fmt.Errorf("cannot find file %v", name)
+			continue
 		}
+		return f, joined, nil
+	}
+	if firstErr != nil {
+		return nil, "", firstErr
 	}
-	return accessor
+	return nil, "", &IncludeNotFoundError{Name: name, Paths: paths}
 }
 
 // FileAccessor abstracts how a file is opened.
 type FileAccessor func(filename string) (io.ReadCloser, error)
+
+// SourceAccessorFromMap returns a file accessor opening the files from srcs, which maps
+// file names to their content. File names are compared once cleaned, so that "./a.fbs"
+// and "a.fbs" are the same file. It is meant for tests and for sources which do not come
+// from the file system. Example:
+//
+//	p := fbs.NewParser()
+//	p.SetAccessor(fbs.SourceAccessorFromMap(map[string]string{
+//		"greeter.fbs": `include "common.fbs"; ...`,
+//		"common.fbs":  `...`,
+//	}))
+//	fds, err := p.ParseFiles("greeter.fbs")
+func SourceAccessorFromMap(srcs map[string]string) FileAccessor {
+	files := make(map[string]string, len(srcs))
+	for name, src := range srcs {
+		files[filepath.Clean(name)] = src
+	}
+	return func(filename string) (io.ReadCloser, error) {
+		src, ok := files[filepath.Clean(filename)]
+		if !ok {
+			return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrNotExist}
+		}
+		return ioutil.NopCloser(strings.NewReader(src)), nil
+	}
+}
@@ -14,6 +14,12 @@
 package fbs
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,19 +58,23 @@ func TestSimpleParse(t *testing.T) {
 	assert.Equal(t, "Red", red.Name)
 	assert.Equal(t, "Green", green.Name)
 	assert.Equal(t, "Blue", blue.Name)
-	assert.Equal(t, int32(0), red.Number)
-	assert.Equal(t, int32(1), green.Number)
-	assert.Equal(t, int32(3), blue.Number)
+	assert.Equal(t, int64(0), red.Number)
+	assert.Equal(t, int64(1), green.Number)
+	assert.Equal(t, int64(3), blue.Number)
 	// Check unions.
 	assert.Equal(t, 1, len(fd.Unions))
 	union := fd.Unions[0]
 	assert.Equal(t, "mynamespace", union.Namespace)
 	assert.Equal(t, "Any", union.Name)
-	assert.Equal(t, "MyTable1", union.Values[0].TypeName)
-	assert.Equal(t, "MyTable2", union.Values[1].TypeName)
+	assert.Equal(t, ".mynamespace.MyTable1", union.Values[0].TypeName)
+	assert.Equal(t, ".mynamespace.MyTable2", union.Values[1].TypeName)
+	assert.Equal(t, int64(1), union.Values[0].Number)
+	assert.Equal(t, int64(2), union.Values[1].Number)
 	// Check tables.
 	assert.Equal(t, 2, len(fd.Tables))
 	table1, table2 := fd.Tables[0], fd.Tables[1]
+	assert.Equal(t, table1, union.Values[0].TypeDesc)
+	assert.Equal(t, table2, union.Values[1].TypeDesc)
 	assert.Equal(t, "mynamespace", table1.Namespace)
 	assert.Equal(t, "MyTable1", table1.Name)
 	assert.Equal(t, 2, len(table1.Fields))
@@ -269,6 +279,60 @@ func TestMonsterParse(t *testing.T) {
 	// Check file identifier.
 	assert.Equal(t, "MONS", fd.FileIdent)
 	assert.Equal(t, "mon", fd.FileExt)
+	// Check documentation.
+	color := fd.Enums[0]
+	assert.Equal(t, []string{" Composite components of Monster color."}, color.Documentation)
+	assert.Nil(t, color.Values[0].Documentation)
+	assert.Equal(t, []string{` \brief color Green`, " Green is bit_flag with value (1u << 1)"},
+		color.Values[1].Documentation)
+	assert.Equal(t, []string{` an example documentation comment: "monster object"`}, fd.Tables[5].Documentation)
+	assert.Nil(t, fd.Tables[5].Fields[6].Documentation)
+	assert.Equal(t, []string{" an example documentation comment: this will end up in the generated code",
+		" multiline too"}, fd.Tables[5].Fields[7].Documentation)
+	// Check root type.
+	assert.Equal(t, "Monster", fd.Root)
+	assert.Equal(t, fd.Tables[5], fd.RootDesc)
+	// Check default values.
+	monster := fd.Tables[5]
+	assert.Equal(t, int64(100), monster.Fields[1].Default)
+	assert.Equal(t, int64(150), monster.Fields[2].Default)
+	assert.Nil(t, monster.Fields[3].Default)
+	blue, ok := monster.Fields[4].Default.(*EnumValDesc)
+	assert.True(t, ok)
+	assert.Equal(t, "Blue", blue.Name)
+	assert.Equal(t, false, monster.Fields[6].Default)
+	assert.Equal(t, 3.14159, monster.Fields[27].Default)
+	assert.Equal(t, float64(3), monster.Fields[28].Default)
+	none, ok := monster.Fields[45].Default.(*EnumValDesc)
+	assert.True(t, ok)
+	assert.Equal(t, "None", none.Name)
+	// Check field attributes.
+	assert.Equal(t, 2, monster.Fields[1].ID)
+	assert.Equal(t, 1, monster.Fields[2].ID)
+	assert.True(t, monster.Fields[3].IsKey)
+	assert.True(t, monster.Fields[6].IsDeprecated)
+	assert.False(t, monster.Fields[5].IsDeprecated)
+	assert.Equal(t, "test", monster.Fields[13].Name)
+	assert.Equal(t, 8, monster.Fields[13].ID)
+	assert.Equal(t, monster, monster.Fields[16].NestedFlatbuffer)
+	assert.Equal(t, "fnv1_32", monster.Fields[19].Hash)
+	// Check resolved field types.
+	assert.Equal(t, fd.Structs[1], monster.Fields[0].TypeDesc)
+	assert.Equal(t, FieldType{BaseType: BaseTypeStruct}, monster.Fields[0].Type)
+	assert.Nil(t, monster.Fields[1].TypeDesc)
+	assert.Equal(t, FieldType{BaseType: BaseTypeShort}, monster.Fields[1].Type)
+	assert.Equal(t, fd.Enums[0], monster.Fields[4].TypeDesc)
+	assert.Equal(t, FieldType{BaseType: BaseTypeUbyte}, monster.Fields[4].Type)
+	assert.Equal(t, FieldType{BaseType: BaseTypeVector, ElementType: BaseTypeUbyte}, monster.Fields[5].Type)
+	assert.Equal(t, monster, monster.Fields[7].TypeDesc)
+	assert.Equal(t, FieldType{BaseType: BaseTypeVector, ElementType: BaseTypeTable}, monster.Fields[7].Type)
+	assert.Equal(t, FieldType{BaseType: BaseTypeVector, ElementType: BaseTypeString}, monster.Fields[8].Type)
+	assert.Equal(t, fd.Unions[0], monster.Fields[13].TypeDesc)
+	assert.Equal(t, FieldType{BaseType: BaseTypeUnion}, monster.Fields[13].Type)
+	stat := fd.Tables[3]
+	assert.Equal(t, 2, stat.Fields[2].ID)
+	assert.True(t, stat.Fields[2].IsKey)
+	assert.False(t, stat.Fields[0].IsKey)
 }
 
 func TestMonsterExtraParse(t *testing.T) {
@@ -366,6 +430,23 @@ func TestOptionalScalarsParse(t *testing.T) {
 	assert.Equal(t, 1, len(fd.Tables))
 	assert.Equal(t, "ScalarStuff", fd.Tables[0].Name)
 	assert.Equal(t, 36, len(fd.Tables[0].Fields))
+	// Check default values.
+	fs := fd.Tables[0].Fields
+	assert.Nil(t, fs[0].Default)
+	assert.False(t, fs[0].DefaultIsNull)
+	assert.Nil(t, fs[1].Default)
+	assert.True(t, fs[1].DefaultIsNull)
+	assert.False(t, fs[0].IsOptional)
+	assert.True(t, fs[1].IsOptional)
+	assert.Equal(t, int64(42), fs[2].Default)
+	assert.Equal(t, uint64(42), fs[5].Default)
+	assert.Equal(t, float64(42), fs[26].Default)
+	assert.True(t, fs[31].DefaultIsNull)
+	assert.Equal(t, true, fs[32].Default)
+	assert.True(t, fs[34].DefaultIsNull)
+	one, ok := fs[35].Default.(*EnumValDesc)
+	assert.True(t, ok)
+	assert.Equal(t, "One", one.Name)
 	// Check file identifier.
 	assert.Equal(t, "NULL", fd.FileIdent)
 	// Check file extension.
@@ -396,6 +477,19 @@ func TestUnionVectorParse(t *testing.T) {
 	// Check unions.
 	assert.Equal(t, 1, len(fd.Unions))
 	assert.Equal(t, "Character", fd.Unions[0].Name)
+	// Check union members.
+	members := fd.Unions[0].Values
+	assert.Equal(t, 6, len(members))
+	assert.Equal(t, "MuLan", members[0].Name)
+	assert.Equal(t, fd.Tables[0], members[0].TypeDesc)
+	assert.Equal(t, "", members[1].Name)
+	assert.Equal(t, fd.Structs[0], members[1].TypeDesc)
+	assert.Equal(t, "string", members[4].TypeName)
+	assert.Nil(t, members[4].TypeDesc)
+	assert.Equal(t, int64(6), members[5].Number)
+	// Check field ids, a union field takes two ids.
+	assert.Equal(t, 1, fd.Tables[1].Fields[0].ID)
+	assert.Equal(t, 3, fd.Tables[1].Fields[1].ID)
 	// Check file identifier.
 	assert.Equal(t, "MOVI", fd.FileIdent)
 }
@@ -423,6 +517,326 @@ func TestErrorParse(t *testing.T) {
 	assert.Nil(t, fds)
 }
 
+func TestFieldDefaultErrorParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{
+			name:     "default value out of range",
+			filename: "./fbsfiles/error_test/parse_test3.fbs",
+			wantErr:  "field MyTable.hp: value 40000 is out of range: [-32768,32767]",
+		},
+		{
+			name:     "default value for string field",
+			filename: "./fbsfiles/error_test/parse_test4.fbs",
+			wantErr:  "field MyTable.name: default value is only allowed for scalar fields",
+		},
+		{
+			name:     "floating point default value for integer field",
+			filename: "./fbsfiles/error_test/parse_test5.fbs",
+			wantErr:  "field MyTable.hp: value 1.5 is not a valid int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			fds, err := p.ParseFiles(tt.filename)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, fds)
+		})
+	}
+}
+
+func TestEnumParse(t *testing.T) {
+	p := NewParser()
+	fds, err := p.ParseFiles("./fbsfiles/enum_test.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	fd := fds[0]
+	assert.Equal(t, 3, len(fd.Enums))
+	big := fd.Enums[0]
+	assert.Equal(t, "ulong", big.TypeName)
+	assert.Equal(t, BaseTypeUlong, big.BaseType)
+	assert.Equal(t, int64(0), big.Values[0].Number)
+	assert.Equal(t, uint64(math.MaxUint64), big.Values[1].Uint64())
+	signed := fd.Enums[1]
+	assert.Equal(t, BaseTypeLong, signed.BaseType)
+	assert.Equal(t, int64(math.MinInt64), signed.Values[0].Number)
+	assert.Equal(t, int64(math.MinInt64+1), signed.Values[1].Number)
+	flags := fd.Enums[2]
+	assert.Equal(t, BaseTypeUshort, flags.BaseType)
+	assert.Equal(t, int64(15), flags.Values[1].Number)
+	// Check enum default values.
+	holder := fd.Tables[0]
+	assert.Equal(t, big.Values[1], holder.Fields[0].Default)
+	assert.Equal(t, flags.Values[1], holder.Fields[1].Default)
+}
+
+func TestEnumErrorParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{
+			name:     "explicit value out of range",
+			filename: "./fbsfiles/error_test/parse_test9.fbs",
+			wantErr:  "enum MyEnum: value 300 is out of range: [0,255]",
+		},
+		{
+			name:     "implicit value out of range",
+			filename: "./fbsfiles/error_test/parse_test10.fbs",
+			wantErr:  "enum MyEnum: implicit value of B overflows byte",
+		},
+		{
+			name:     "non-integral underlying type",
+			filename: "./fbsfiles/error_test/parse_test11.fbs",
+			wantErr:  "enum MyEnum: underlying type must be an integral type",
+		},
+		{
+			name:     "bit flag out of range",
+			filename: "./fbsfiles/error_test/parse_test12.fbs",
+			wantErr:  "enum MyEnum: bit flag 8 is out of range: [0,7]",
+		},
+		{
+			name:     "default value out of range of enum",
+			filename: "./fbsfiles/error_test/link_test29.fbs",
+			wantErr:  "field MyTable.e: default value of enum MyEnum: value 256 is out of range: [0,255]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			fds, err := p.ParseFiles(tt.filename)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, fds)
+		})
+	}
+}
+
+func TestRootTypeParse(t *testing.T) {
+	p := NewParser()
+	fds, err := p.ParseFiles("./fbsfiles/root_type_test.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	fd := fds[0]
+	assert.Equal(t, "game.example.Monster", fd.Root)
+	assert.Equal(t, fd.Tables[0], fd.RootDesc)
+	assert.Equal(t, "GAME", fd.FileIdent)
+	assert.Equal(t, "gm", fd.FileExt)
+}
+
+func TestRootTypeErrorParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{
+			name:     "unknown root type",
+			filename: "./fbsfiles/error_test/link_test33.fbs",
+			wantErr:  "root_type: unknown type Nope",
+		},
+		{
+			name:     "root type is a struct",
+			filename: "./fbsfiles/error_test/link_test34.fbs",
+			wantErr:  "root_type: invalid type: MyStruct is a struct, not a table",
+		},
+		{
+			name:     "file identifier is not 4 bytes",
+			filename: "./fbsfiles/error_test/parse_test14.fbs",
+			wantErr:  `file_identifier "ABCDE" must be exactly 4 bytes`,
+		},
+		{
+			name:     "file extension starts with a dot",
+			filename: "./fbsfiles/error_test/parse_test15.fbs",
+			wantErr:  `file_extension ".mon" must be non-empty and must not start with a dot`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			fds, err := p.ParseFiles(tt.filename)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, fds)
+		})
+	}
+}
+
+func TestUnionErrorParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{
+			name:     "unknown member type",
+			filename: "./fbsfiles/error_test/link_test30.fbs",
+			wantErr:  "union Any: unknown type Monstr",
+		},
+		{
+			name:     "enum member type",
+			filename: "./fbsfiles/error_test/link_test31.fbs",
+			wantErr:  "union Any: invalid member type: Color is a enum, must be a table, struct or string",
+		},
+		{
+			name:     "scalar member type",
+			filename: "./fbsfiles/error_test/link_test32.fbs",
+			wantErr:  "union Any: invalid member type int, must be a table, struct or string",
+		},
+		{
+			name:     "duplicate member",
+			filename: "./fbsfiles/error_test/parse_test13.fbs",
+			wantErr:  "union Any: duplicate member Monster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			fds, err := p.ParseFiles(tt.filename)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, fds)
+		})
+	}
+}
+
+func TestFieldAttributeErrorParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{
+			name:     "non-integer id",
+			filename: "./fbsfiles/error_test/parse_test6.fbs",
+			wantErr:  "field MyTable.hp: attribute id: value a is not a valid ushort",
+		},
+		{
+			name:     "hash algorithm does not match field type",
+			filename: "./fbsfiles/error_test/parse_test7.fbs",
+			wantErr:  "field MyTable.hp: hash algorithm fnv1_64 does not match the 32-bit type int",
+		},
+		{
+			name:     "nested flatbuffer on non ubyte vector",
+			filename: "./fbsfiles/error_test/parse_test8.fbs",
+			wantErr:  "field MyTable.nested: nested_flatbuffer attribute may only apply to a vector of ubyte",
+		},
+		{
+			name:     "required scalar field",
+			filename: "./fbsfiles/error_test/link_test23.fbs",
+			wantErr:  "field MyTable.hp: only non-scalar fields in tables may be required",
+		},
+		{
+			name:     "more than one key",
+			filename: "./fbsfiles/error_test/link_test24.fbs",
+			wantErr:  "field MyTable.name: only one field may be set as key, hp is already the key",
+		},
+		{
+			name:     "id given on part of fields",
+			filename: "./fbsfiles/error_test/link_test25.fbs",
+			wantErr:  "field MyTable.name: either all fields or no fields must have an id attribute",
+		},
+		{
+			name:     "field ids are not contiguous",
+			filename: "./fbsfiles/error_test/link_test26.fbs",
+			wantErr:  "table MyTable: field ids must be contiguous from 0, id 1 is missing",
+		},
+		{
+			name:     "union field takes two ids",
+			filename: "./fbsfiles/error_test/link_test27.fbs",
+			wantErr:  "field MyTable.u: id 0 is already used by field hp",
+		},
+		{
+			name:     "nested flatbuffer is not a table",
+			filename: "./fbsfiles/error_test/link_test28.fbs",
+			wantErr:  "field MyTable.nested: invalid nested flatbuffer type: MyStruct is a struct, not a table",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			fds, err := p.ParseFiles(tt.filename)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, fds)
+		})
+	}
+}
+
+func TestHashParse(t *testing.T) {
+	p := NewParser()
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{
+		"hash.fbs": "table T {\n  a:short (hash: \"fnv1_16\");\n  b:ushort (hash: \"fnv1a_16\");\n" +
+			"  c:uint (hash: \"fnv1a_32\");\n  d:long (hash: \"fnv1_64\");\n}\n",
+		"hash_error.fbs": "table T {\n  a:int (hash: \"fnv1_16\");\n}\n",
+		"byte_error.fbs": "table T {\n  a:byte (hash: \"fnv1_16\");\n}\n",
+	}))
+	fds, err := p.ParseFiles("hash.fbs")
+	assert.Nil(t, err)
+	fields := fds[0].Tables[0].Fields
+	assert.Equal(t, "fnv1_16", fields[0].Hash)
+	assert.Equal(t, "fnv1a_16", fields[1].Hash)
+	assert.Equal(t, "fnv1a_32", fields[2].Hash)
+	assert.Equal(t, "fnv1_64", fields[3].Hash)
+
+	_, err = p.ParseFiles("hash_error.fbs")
+	assert.Equal(t, "hash_error.fbs:2:16: field T.a: hash algorithm fnv1_16 does not match the 32-bit type int",
+		err.Error())
+	_, err = p.ParseFiles("byte_error.fbs")
+	assert.Equal(t, "byte_error.fbs:2:11: field T.a: "+
+		"only short, ushort, int, uint, long and ulong fields support hashing", err.Error())
+}
+
+func TestMetadataParse(t *testing.T) {
+	filenames := []string{
+		"./fbsfiles/metadata_test.fbs",
+	}
+	p := NewParser()
+	fds, err := p.ParseFiles(filenames...)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	fd := fds[0]
+	// Check enum and enum values.
+	enum := fd.Enums[0]
+	assert.True(t, enum.Metadata.Has("bit_flags"))
+	assert.Nil(t, enum.Values[0].Metadata)
+	assert.True(t, enum.Values[1].Metadata.Has("deprecated"))
+	v, ok := enum.Values[2].Metadata.GetString("myattr")
+	assert.True(t, ok)
+	assert.Equal(t, "blue", v)
+	// Check union.
+	assert.True(t, fd.Unions[0].Metadata.Has("myattr"))
+	// Check struct.
+	align, ok := fd.Structs[0].Metadata.GetInt("force_align")
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), align)
+	assert.Nil(t, fd.Structs[0].Fields[0].Metadata)
+	// Check table and fields.
+	table := fd.Tables[0]
+	i, ok := table.Metadata.GetInt("myattr")
+	assert.True(t, ok)
+	assert.Equal(t, int64(-1), i)
+	fs := table.Fields
+	assert.Equal(t, []string{"id", "key"}, fs[0].Metadata.Keys)
+	assert.True(t, fs[0].Metadata.Has("key"))
+	assert.True(t, fs[1].Metadata.Has("required"))
+	_, ok = fs[1].Metadata.GetInt("myattr")
+	assert.False(t, ok)
+	assert.True(t, fs[2].Metadata.Has("deprecated"))
+	// Check rpc service.
+	rpc := fd.RPCs[0]
+	assert.Equal(t, 1.5, rpc.Metadata.KV["myattr"])
+	_, ok = rpc.Metadata.GetString("myattr")
+	assert.False(t, ok)
+	assert.False(t, rpc.Methods[0].ClientStreaming)
+}
+
 func TestEmptyFileParse(t *testing.T) {
 	filenames := []string{
 		"./fbsfiles/error_test/empty_test.fbs",
@@ -431,6 +845,35 @@ func TestEmptyFileParse(t *testing.T) {
 	fds, err := p.ParseFiles(filenames...)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(fds))
+	r, ok := fds[0].SourceInfo(fds[0])
+	assert.True(t, ok)
+	assert.Equal(t, "./fbsfiles/error_test/empty_test.fbs", r.Start.String())
+}
+
+func TestSourceInfo(t *testing.T) {
+	p := NewParser()
+	fds, err := p.ParseFiles("./fbsfiles/simple_test1.fbs")
+	assert.Nil(t, err)
+	fd := fds[0]
+	table := fd.Tables[0]
+	r, ok := fd.SourceInfo(table)
+	assert.True(t, ok)
+	assert.Equal(t, "./fbsfiles/simple_test1.fbs:15:1", r.Start.String())
+	assert.Equal(t, 18, r.End.Line)
+	rpc := fd.RPCs[0]
+	r, ok = fd.SourceInfo(rpc)
+	assert.True(t, ok)
+	assert.Equal(t, "./fbsfiles/simple_test1.fbs:27:1", r.Start.String())
+	assert.Equal(t, "./fbsfiles/simple_test1.fbs:33:2", r.End.String())
+	r, ok = fd.SourceInfo(rpc.Methods[1].Metadata)
+	assert.True(t, ok)
+	assert.Equal(t, "./fbsfiles/simple_test1.fbs:29:34", r.Start.String())
+	assert.Equal(t, "./fbsfiles/simple_test1.fbs:29:53", r.End.String())
+	r, ok = fd.SourceInfo(table.Fields[0])
+	assert.True(t, ok)
+	assert.Equal(t, "./fbsfiles/simple_test1.fbs:16:3", r.Start.String())
+	_, ok = fd.SourceInfo(&TableDesc{})
+	assert.False(t, ok)
 }
 
 func TestIncludeErrorFileParse(t *testing.T) {
@@ -444,6 +887,89 @@ func TestIncludeErrorFileParse(t *testing.T) {
 	assert.Nil(t, fds)
 }
 
+func TestAllErrorsParse(t *testing.T) {
+	filenames := []string{
+		"./fbsfiles/error_test/link_test35.fbs",
+		"./fbsfiles/error_test/link_test36.fbs", // includes parse_test16.fbs
+	}
+	p := NewParser()
+	fds, err := p.ParseFiles(filenames...)
+	assert.Nil(t, fds)
+	assert.Equal(t, "parse_test16.fbs:1:16: syntax error: missing ';' after field a", err.Error())
+
+	p = NewParser()
+	p.SetAllErrors(true)
+	fds, err = p.ParseFiles(filenames...)
+	assert.Nil(t, fds)
+	var errs ErrorList
+	assert.True(t, errors.As(err, &errs))
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	// link_test36.fbs includes a file with errors, so it is not linked and the
+	// unknown type in it is not reported.
+	want := []string{
+		"./fbsfiles/error_test/link_test35.fbs:2:3: field T.a: unknown type Unknown",
+		"./fbsfiles/error_test/link_test35.fbs:4:3: field T.c: unknown type Unknown2",
+		"./fbsfiles/error_test/link_test35.fbs:6:18: field K.k: only non-scalar fields in tables may be required",
+		"./fbsfiles/error_test/link_test35.fbs:6:38: field K.s: only scalar or string fields may be set as key",
+		"./fbsfiles/error_test/link_test35.fbs:7:14: union V: unknown type W",
+		"./fbsfiles/error_test/link_test35.fbs:8:11: root_type: unknown type Nope",
+		"parse_test16.fbs:1:16: syntax error: missing ';' after field a",
+		"parse_test16.fbs:3:14: syntax error: unexpected 'int', expected ':'",
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, strings.Join(want, "\n"), err.Error())
+}
+
+func TestAllErrorsLexerParse(t *testing.T) {
+	p := NewParser()
+	p.SetAllErrors(true)
+	_, err := p.ParseFiles("./fbsfiles/error_test/parse_test17.fbs")
+	var errs ErrorList
+	assert.True(t, errors.As(err, &errs))
+	// the lexer error is not reported again as a syntax error.
+	assert.Equal(t, 2, len(errs))
+	assert.Contains(t, errs[0].Error(), "parse_test17.fbs:1:20: invalid escape sequence")
+	assert.Equal(t, "./fbsfiles/error_test/parse_test17.fbs:2:16: syntax error: missing ';' after field b",
+		errs[1].Error())
+}
+
+func TestAllErrorsMissingFileParse(t *testing.T) {
+	p := NewParser()
+	p.SetAllErrors(true)
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{"b.fbs": "table B {}\n"}))
+	_, err := p.ParseFiles("a.fbs", "b.fbs")
+	var errs ErrorList
+	assert.True(t, errors.As(err, &errs))
+	var notFound *IncludeNotFoundError
+	assert.True(t, errors.As(errs[0], &notFound))
+	assert.Equal(t, "a.fbs: cannot find file a.fbs", err.Error())
+}
+
+func TestAllErrorsSemanticParse(t *testing.T) {
+	p := NewParser()
+	p.SetAllErrors(true)
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{
+		"a.fbs": "table T { hp:short = 40000; a:Unknown; }\n",
+	}))
+	_, err := p.ParseFiles("a.fbs")
+	var errs ErrorList
+	assert.True(t, errors.As(err, &errs))
+	// the file has no syntax errors, so it is linked and the unknown type is reported too.
+	assert.Equal(t, "a.fbs:1:22: field T.hp: value 40000 is out of range: [-32768,32767]\n"+
+		"a.fbs:1:29: field T.a: unknown type Unknown", err.Error())
+}
+
+func TestAllErrorsParseOK(t *testing.T) {
+	p := NewParser()
+	p.SetAllErrors(true)
+	fds, err := p.ParseFiles("./fbsfiles/monster_test.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+}
+
 func TestNonrecursiveParse(t *testing.T) {
 	filenames := []string{
 		"./fbsfiles/error_test/parse_test2.fbs",
@@ -455,6 +981,58 @@ func TestNonrecursiveParse(t *testing.T) {
 	assert.Equal(t, 1, len(fds))
 }
 
+func TestNonrecursiveMultiFileParse(t *testing.T) {
+	p := NewParser()
+	p.SetRecursive(false)
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{
+		"a.fbs":        "include \"nested/b.fbs\";\ntable A { b:B; c:C; }\n",
+		"nested/b.fbs": "include \"../c.fbs\";\ntable B { c:C; }\n",
+		"c.fbs":        "table C {}\n",
+	}))
+	fds, err := p.ParseFiles("a.fbs", "nested/b.fbs", "./c.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(fds))
+	a, b, c := fds[0], fds[1], fds[2]
+	assert.Equal(t, b.Tables[0], a.Tables[0].Fields[0].TypeDesc)
+	assert.Equal(t, c.Tables[0], a.Tables[0].Fields[1].TypeDesc)
+	assert.Equal(t, c.Tables[0], b.Tables[0].Fields[0].TypeDesc)
+
+	// Included files that are not given are not parsed.
+	_, err = p.ParseFiles("a.fbs", "nested/b.fbs")
+	assert.NotNil(t, err)
+}
+
+func TestDocumentationParse(t *testing.T) {
+	p := NewParser()
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{
+		"doc.fbs": "////////////////\n/// T is documented.\n///\n//// not documentation\n" +
+			"table T {\n  /// a\n  //// b\n  a:int;\n}\n",
+	}))
+	fds, err := p.ParseFiles("doc.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{" T is documented.", ""}, fds[0].Tables[0].Documentation)
+	assert.Equal(t, []string{" a"}, fds[0].Tables[0].Fields[0].Documentation)
+}
+
+func TestValueOrderParse(t *testing.T) {
+	p := NewParser()
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{
+		"order.fbs": "enum E : byte { C = 5, A, B, }\ntable X {}\ntable Y {}\nunion U { Y, X }\n",
+	}))
+	fds, err := p.ParseFiles("order.fbs")
+	assert.Nil(t, err)
+	var names []string
+	for _, v := range fds[0].Enums[0].Values {
+		names = append(names, fmt.Sprintf("%s=%d", v.Name, v.Number))
+	}
+	assert.Equal(t, []string{"C=5", "A=6", "B=7"}, names)
+	names = nil
+	for _, v := range fds[0].Unions[0].Values {
+		names = append(names, fmt.Sprintf("%s=%d", v.TypeName, v.Number))
+	}
+	assert.Equal(t, []string{".Y=1", ".X=2"}, names)
+}
+
 func TestCustomParse(t *testing.T) {
 	filenames := []string{
 		"./fbsfiles/custom_test.fbs",
@@ -464,3 +1042,78 @@ func TestCustomParse(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(fds))
 }
+
+func TestAccessorParse(t *testing.T) {
+	accessor := SourceAccessorFromMap(map[string]string{
+		"greeter.fbs": "include \"common/hello.fbs\";\nnamespace greeter;\n" +
+			"rpc_service Greeter { SayHello(hello.Request):hello.Reply; }\n",
+		"./common/hello.fbs": "include \"types.fbs\";\nnamespace hello;\ntable Request { t:Type; }\ntable Reply {}\n",
+		"common/types.fbs":   "namespace hello;\ntable Type {}\n",
+		"broken.fbs":         "include \"missing.fbs\";\n",
+		"common/unknown.fbs": "table A { a:Unknwon; }\n",
+	})
+	p := NewParser("common")
+	p.SetAccessor(accessor)
+	fds, err := p.ParseFiles("greeter.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	assert.Equal(t, "hello.Request", fds[0].RPCs[0].Methods[0].InputTypeDesc.FullName())
+
+	_, err = p.ParseFiles("broken.fbs")
+	var notFound *IncludeNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "broken.fbs:1:9: cannot find file missing.fbs", err.Error())
+
+	_, err = p.ParseFiles("unknown.fbs")
+	assert.NotNil(t, err)
+	r := &ErrorRenderer{Accessor: func(name string) (io.ReadCloser, error) {
+		f, _, err := openFile(accessor, p.paths, name)
+		return f, err
+	}}
+	assert.Contains(t, r.RenderString(err), "1 | table A { a:Unknwon; }\n")
+
+	failure := errors.New("database unavailable")
+	p.SetAccessor(func(filename string) (io.ReadCloser, error) {
+		return nil, failure
+	})
+	_, err = p.ParseFiles("greeter.fbs")
+	assert.Equal(t, failure, err)
+}
+
+func TestRelativeIncludeParse(t *testing.T) {
+	p := NewParser()
+	fds, err := p.ParseFiles("./fbsfiles/include_test/main.fbs", "fbsfiles/include_test/common/base.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fds))
+	main, base := fds[0], fds[1]
+	assert.Equal(t, "./fbsfiles/include_test/main.fbs", main.Name)
+	assert.True(t, filepath.IsAbs(main.Path))
+	assert.Equal(t, 2, len(main.Dependencies))
+	a := main.Dependencies[0]
+	assert.Equal(t, "nested/a.fbs", a.Name)
+	// base.fbs is included as "../common/base.fbs" by a.fbs, as "common/base.fbs" by main.fbs
+	// and given to the parser, it is parsed once under the name it is first reached by.
+	assert.Equal(t, base, main.Dependencies[1])
+	assert.Equal(t, []*SchemaDesc{base}, a.Dependencies)
+	assert.Equal(t, "../common/base.fbs", base.Name)
+	assert.Equal(t, base.Tables[0], main.Tables[0].Fields[1].TypeDesc)
+	assert.Equal(t, base.Tables[0], a.Tables[0].Fields[0].TypeDesc)
+
+	p = NewParser()
+	p.SetAccessor(SourceAccessorFromMap(map[string]string{
+		"main.fbs":          "include \"nested/a.fbs\";\ninclude \"./nested/../common/base.fbs\";\n",
+		"nested/a.fbs":      "include \"../common/base.fbs\";\ntable A { b:Base; }\n",
+		"common/base.fbs":   "table Base {}\n",
+		"nested/broken.fbs": "include \"base.fbs\";\n",
+	}))
+	fds, err = p.ParseFiles("main.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, "main.fbs", fds[0].Path)
+	assert.Equal(t, "common/base.fbs", fds[0].Dependencies[1].Path)
+	assert.Equal(t, fds[0].Dependencies[0].Dependencies[0], fds[0].Dependencies[1])
+
+	_, err = p.ParseFiles("nested/broken.fbs")
+	var notFound *IncludeNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, []string{"nested", "", "nested/"}, notFound.Paths)
+}
@@ -0,0 +1,284 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"trpc.group/trpc-go/fbs/ast"
+)
+
+// ANSI escape sequences used when colors are enabled.
+const (
+	colorReset   = "\x1b[0m"
+	colorBold    = "\x1b[1m"
+	colorError   = "\x1b[1;31m"
+	colorWarning = "\x1b[1;33m"
+	colorGutter  = "\x1b[1;34m"
+)
+
+// ErrorRenderer prints errors along with the source text they are about. Example:
+//
+//	error: field MyGame.Monster.pos: unknown type Vce3; did you mean MyGame.Vec3?
+//	 --> monster.fbs:12:3
+//	   |
+//	12 |   pos:Vce3;
+//	   |   ^^^^^^^^^
+type ErrorRenderer struct {
+	// Colors enables ANSI colors.
+	Colors bool
+	// Accessor opens the files the errors refer to. Files are opened with os.Open if nil.
+	Accessor FileAccessor
+
+	sources map[string][]string // Lines of the files read, by filename.
+}
+
+// label is a position related to an error, such as the previous definition of a symbol.
+type label struct {
+	pos *ast.Position
+	msg string
+}
+
+// relatedError is implemented by errors which refer to other positions in the source.
+type relatedError interface {
+	related() []label
+}
+
+// Render writes err to w, each error of an ErrorList in turn. The source text is shown
+// whenever the position of an error is known and its file can be read. Nothing is written
+// if err is nil.
+func (r *ErrorRenderer) Render(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+	var list ErrorList
+	if !errors.As(err, &list) {
+		var ewp ErrorWithPos
+		if errors.As(err, &ewp) {
+			list = ErrorList{ewp}
+		}
+	}
+	r.sources = nil
+	var buf bytes.Buffer
+	if len(list) == 0 {
+		r.header(&buf, err)
+	}
+	for i, ewp := range list {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		r.render(&buf, ewp)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// RenderString returns err as written by Render.
+func (r *ErrorRenderer) RenderString(err error) string {
+	var sb strings.Builder
+	_ = r.Render(&sb, err)
+	return sb.String()
+}
+
+// render writes a single error, its source text and the related positions.
+func (r *ErrorRenderer) render(buf *bytes.Buffer, ewp ErrorWithPos) {
+	r.header(buf, ewp.Err)
+	if ewp.Pos == nil {
+		return
+	}
+	labels := []label{{pos: ewp.Pos}}
+	var rel relatedError
+	if errors.As(ewp.Err, &rel) {
+		for _, l := range rel.related() {
+			if l.pos != nil {
+				labels = append(labels, l)
+			}
+		}
+	}
+	width := 0
+	for _, l := range labels {
+		if n := len(strconv.Itoa(l.pos.Line)); n > width {
+			width = n
+		}
+	}
+	gutter := strings.Repeat(" ", width)
+	for i, l := range labels {
+		arrow := "-->"
+		if i > 0 {
+			arrow = ":::"
+		}
+		fmt.Fprintf(buf, "%s%s %s\n", gutter, r.color(colorGutter, arrow), l.pos)
+		line, ok := r.line(l.pos)
+		if !ok {
+			continue
+		}
+		// Related positions are the start of declarations, they are marked up to the end of line.
+		end := &ast.Position{Line: l.pos.Line + 1}
+		mark, color := "-", colorGutter
+		if i == 0 {
+			end, mark, color = ewp.End, "^", r.severity(ewp.Err)
+		}
+		start, n := span(line, l.pos, end)
+		fmt.Fprintf(buf, "%s %s\n", gutter, r.color(colorGutter, "|"))
+		fmt.Fprintf(buf, "%*d %s %s\n", width, l.pos.Line, r.color(colorGutter, "|"), line)
+		underline := strings.Repeat(" ", start) + strings.Repeat(mark, n)
+		if l.msg != "" {
+			underline += " " + l.msg
+		}
+		fmt.Fprintf(buf, "%s %s %s\n", gutter, r.color(colorGutter, "|"), r.color(color, underline))
+	}
+}
+
+// header writes the severity and message of err.
+func (r *ErrorRenderer) header(buf *bytes.Buffer, err error) {
+	severity := "error"
+	var w *Warning
+	if errors.As(err, &w) {
+		severity = "warning"
+	}
+	fmt.Fprintf(buf, "%s%s\n", r.color(r.severity(err), severity+":"), r.color(colorBold, " "+err.Error()))
+}
+
+// severity returns the color of err.
+func (r *ErrorRenderer) severity(err error) string {
+	var w *Warning
+	if errors.As(err, &w) {
+		return colorWarning
+	}
+	return colorError
+}
+
+// color wraps s in the ANSI escape sequence c if colors are enabled.
+func (r *ErrorRenderer) color(c, s string) string {
+	if !r.Colors {
+		return s
+	}
+	return c + s + colorReset
+}
+
+// line returns the line at pos with tabs expanded, so that the columns of the lexer are those
+// of the line.
+func (r *ErrorRenderer) line(pos *ast.Position) (string, bool) {
+	lines, ok := r.sources[pos.Filename]
+	if !ok {
+		lines = r.readLines(pos.Filename)
+		if r.sources == nil {
+			r.sources = map[string][]string{}
+		}
+		r.sources[pos.Filename] = lines
+	}
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return "", false
+	}
+	return expandTabs(lines[pos.Line-1]), true
+}
+
+// readLines returns the lines of the named file, none if it can not be read.
+func (r *ErrorRenderer) readLines(filename string) []string {
+	accessor := r.Accessor
+	if accessor == nil {
+		accessor = func(filename string) (io.ReadCloser, error) {
+			return os.Open(filename)
+		}
+	}
+	rc, err := accessor(filename)
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+}
+
+// expandTabs replaces the tabs of line with spaces up to the next tab stop.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var sb strings.Builder
+	col := 0
+	for _, c := range line {
+		if c == '\t' {
+			n := Tabsize - col%Tabsize
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(c)
+		col++
+	}
+	return sb.String()
+}
+
+// span returns the column, counted from 0, and the width of the text of line to underline
+// from pos to end. If end is not known, the token at pos is underlined. If end is on a later
+// line, the text is underlined up to the end of line.
+func span(line string, pos, end *ast.Position) (int, int) {
+	runes := []rune(line)
+	start := pos.Col - 1
+	if start < 0 || start > len(runes) {
+		return 0, 1
+	}
+	n := 0
+	switch {
+	case end != nil && end.Line == pos.Line:
+		n = end.Col - pos.Col
+	case end != nil && end.Line > pos.Line:
+		n = len([]rune(strings.TrimRightFunc(line, unicode.IsSpace))) - start
+	default:
+		n = tokenLen(runes[start:])
+	}
+	if n < 1 {
+		n = 1
+	}
+	return start, n
+}
+
+// tokenLen returns the length of the token at the start of runes: a qualified identifier, a
+// number, a string literal or else a single rune.
+func tokenLen(runes []rune) int {
+	if len(runes) == 0 {
+		return 1
+	}
+	if runes[0] == '"' {
+		for i := 1; i < len(runes); i++ {
+			if runes[i] == '\\' {
+				i++
+			} else if runes[i] == '"' {
+				return i + 1
+			}
+		}
+		return len(runes)
+	}
+	n := 0
+	for n < len(runes) && (unicode.IsLetter(runes[n]) || unicode.IsDigit(runes[n]) || runes[n] == '_' ||
+		(n > 0 && runes[n] == '.')) {
+		n++
+	}
+	if n == 0 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"trpc.group/trpc-go/fbs/ast"
+)
+
+func TestRenderError(t *testing.T) {
+	const dir = "./fbsfiles/error_test/"
+	p := NewParser()
+	_, err := p.ParseFiles(dir + "link_test1.fbs")
+	assert.NotNil(t, err)
+	r := &ErrorRenderer{}
+	assert.Equal(t, `error: duplicate symbol DuplicateTableName: already defined as table
+ --> `+dir+`link_test1.fbs:5:1
+  |
+5 | table DuplicateTableName {
+  | ^^^^^^^^^^^^^^^^^^^^^^^^^^
+ ::: `+dir+`link_test1.fbs:1:1
+  |
+1 | table DuplicateTableName {
+  | -------------------------- previous definition of DuplicateTableName
+`, r.RenderString(err))
+}
+
+func TestRenderErrorList(t *testing.T) {
+	files := map[string]string{
+		"a.fbs": "table A {\n\ta:Unknown;\n\tb:int (required);\n}\n",
+	}
+	r := &ErrorRenderer{Accessor: func(filename string) (io.ReadCloser, error) {
+		src, ok := files[filename]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return ioutil.NopCloser(strings.NewReader(src)), nil
+	}}
+	err := ErrorList{
+		{Pos: &ast.Position{Filename: "a.fbs", Line: 2, Col: 5}, End: &ast.Position{Filename: "a.fbs", Line: 2, Col: 14},
+			Err: errors.New("field A.a: unknown type Unknown")},
+		{Pos: &ast.Position{Filename: "a.fbs", Line: 3, Col: 12},
+			Err: &Warning{Code: WarnDeprecatedField, Err: errors.New("field A.b: required is deprecated")}},
+		{Pos: &ast.Position{Filename: "b.fbs", Line: 1, Col: 1}, Err: errors.New("no source")},
+	}
+	assert.Equal(t, `error: field A.a: unknown type Unknown
+ --> a.fbs:2:5
+  |
+2 |     a:Unknown;
+  |     ^^^^^^^^^
+
+warning: field A.b: required is deprecated [deprecated-field]
+ --> a.fbs:3:12
+  |
+3 |     b:int (required);
+  |            ^^^^^^^^
+
+error: no source
+ --> b.fbs:1:1
+`, r.RenderString(err))
+
+	r.Colors = true
+	out := r.RenderString(err[0])
+	assert.True(t, strings.HasPrefix(out, colorError+"error:"+colorReset+colorBold+" field A.a"), out)
+	assert.Contains(t, out, colorError+"    ^^^^^^^^^"+colorReset)
+	assert.Equal(t, colorError+"error:"+colorReset+colorBold+" no position"+colorReset+"\n",
+		r.RenderString(errors.New("no position")))
+	assert.Equal(t, "", r.RenderString(nil))
+}
+
+func TestSpan(t *testing.T) {
+	line := `  name:string = "a \" b";`
+	pos := func(col int) *ast.Position { return &ast.Position{Line: 1, Col: col} }
+	start, n := span(line, pos(3), nil)
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 4, n)
+	start, n = span(line, pos(17), nil)
+	assert.Equal(t, 16, start)
+	assert.Equal(t, 8, n)
+	_, n = span(line, pos(3), pos(8))
+	assert.Equal(t, 5, n)
+	_, n = span(line, pos(3), &ast.Position{Line: 2, Col: 1})
+	assert.Equal(t, 23, n)
+	assert.Equal(t, "a   b       c", expandTabs("a\tb\t\tc"))
+}
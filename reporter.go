@@ -0,0 +1,87 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import "fmt"
+
+// Reporter handles the errors and warnings found while parsing and linking.
+type Reporter interface {
+	// Error is called for every error. If it returns an error, parsing stops and that error
+	// is returned by ParseFiles. Otherwise parsing goes on to find more errors, and
+	// ParseFiles returns an ErrorList of all the errors reported.
+	Error(err ErrorWithPos) error
+	// Warning is called for every warning. The error wrapped by err is a *Warning.
+	Warning(err ErrorWithPos)
+}
+
+// NewReporter creates a Reporter out of the given functions. If errs is nil, parsing stops
+// at the first error. If warnings is nil, warnings are ignored.
+func NewReporter(errs func(ErrorWithPos) error, warnings func(ErrorWithPos)) Reporter {
+	return reporterFuncs{errs: errs, warnings: warnings}
+}
+
+// reporterFuncs implements Reporter with functions.
+type reporterFuncs struct {
+	errs     func(ErrorWithPos) error
+	warnings func(ErrorWithPos)
+}
+
+// Error implements Reporter.
+func (r reporterFuncs) Error(err ErrorWithPos) error {
+	if r.errs == nil {
+		return err
+	}
+	return r.errs(err)
+}
+
+// Warning implements Reporter.
+func (r reporterFuncs) Warning(err ErrorWithPos) {
+	if r.warnings != nil {
+		r.warnings(err)
+	}
+}
+
+// WarningCode identifies the kind of a warning. Codes are stable, they can be used to
+// filter warnings.
+type WarningCode string
+
+// Warning codes.
+const (
+	// WarnUnusedInclude is reported for an included file none of whose symbols is used.
+	// Files declaring attributes are not reported, as the use of attributes is not tracked.
+	WarnUnusedInclude WarningCode = "unused-include"
+	// WarnDeprecatedField is reported for a deprecated field which is still required or a key.
+	WarnDeprecatedField WarningCode = "deprecated-field"
+	// WarnShadowedName is reported for a type hiding a type of the same name declared in an
+	// enclosing namespace.
+	WarnShadowedName WarningCode = "shadowed-name"
+	// WarnEnumOrder is reported for an enum value which is not greater than the previous one.
+	WarnEnumOrder WarningCode = "enum-order"
+)
+
+// Warning is the error wrapped by ErrorWithPos for warnings.
+type Warning struct {
+	Code WarningCode
+	Err  error
+}
+
+// Error implements the error interface, the code follows the message.
+func (w *Warning) Error() string {
+	return fmt.Sprintf("%v [%s]", w.Err, w.Code)
+}
+
+// Unwrap retrieves the original error.
+func (w *Warning) Unwrap() error {
+	return w.Err
+}
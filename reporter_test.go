@@ -0,0 +1,112 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReporterWarnings(t *testing.T) {
+	var warnings []ErrorWithPos
+	p := NewParser()
+	p.SetReporter(NewReporter(nil, func(err ErrorWithPos) {
+		warnings = append(warnings, err)
+	}))
+	fds, err := p.ParseFiles("./fbsfiles/warning_test/warning_test.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+	var got []string
+	var codes []WarningCode
+	for _, w := range warnings {
+		got = append(got, w.Error())
+		var warning *Warning
+		assert.True(t, errors.As(w, &warning))
+		codes = append(codes, warning.Code)
+	}
+	const file = "./fbsfiles/warning_test/warning_test.fbs"
+	assert.Equal(t, []string{
+		file + ":7:28: field a.T.name: deprecated field is still marked required [deprecated-field]",
+		file + ":8:11: field a.T.id: deprecated field is still marked key [deprecated-field]",
+		file + ":12:37: enum Color: value Green = 1 is not greater than the previous value Red = 2 [enum-order]",
+		file + `:1:9: include "warning_include1.fbs" is not used [unused-include]`,
+		file + ":16:1: table a.b.T shadows table a.T [shadowed-name]",
+	}, got)
+	assert.Equal(t, []WarningCode{WarnDeprecatedField, WarnDeprecatedField, WarnEnumOrder,
+		WarnUnusedInclude, WarnShadowedName}, codes)
+}
+
+func TestWarningsAsErrors(t *testing.T) {
+	p := NewParser()
+	p.SetWarningsAsErrors(true)
+	fds, err := p.ParseFiles("./fbsfiles/warning_test/warning_test.fbs")
+	assert.Nil(t, fds)
+	var warning *Warning
+	assert.True(t, errors.As(err, &warning))
+	assert.Equal(t, WarnDeprecatedField, warning.Code)
+
+	p = NewParser()
+	p.SetWarningsAsErrors(true)
+	p.SetAllErrors(true)
+	_, err = p.ParseFiles("./fbsfiles/warning_test/warning_test.fbs")
+	var errs ErrorList
+	assert.True(t, errors.As(err, &errs))
+	// the file has no syntax errors, so it is linked and the warnings of the linker are
+	// reported as well.
+	assert.Equal(t, 5, len(errs))
+
+	// warnings are not reported without a reporter.
+	p = NewParser()
+	fds, err = p.ParseFiles("./fbsfiles/warning_test/warning_test.fbs")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fds))
+}
+
+func TestReporterErrors(t *testing.T) {
+	const filename = "./fbsfiles/error_test/link_test35.fbs"
+	var reported []ErrorWithPos
+	p := NewParser()
+	p.SetReporter(NewReporter(func(err ErrorWithPos) error {
+		reported = append(reported, err)
+		return nil
+	}, nil))
+	_, err := p.ParseFiles(filename)
+	var errs ErrorList
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, 6, len(errs))
+	assert.Equal(t, 6, len(reported))
+
+	// the reporter stops parsing by returning an error.
+	abort := errors.New("too many errors")
+	reported = nil
+	p = NewParser()
+	p.SetReporter(NewReporter(func(err ErrorWithPos) error {
+		reported = append(reported, err)
+		if len(reported) == 2 {
+			return abort
+		}
+		return nil
+	}, nil))
+	_, err = p.ParseFiles(filename)
+	assert.Equal(t, abort, err)
+	assert.Equal(t, 2, len(reported))
+
+	// a nil error function stops at the first error.
+	p = NewParser()
+	p.SetReporter(NewReporter(nil, nil))
+	_, err = p.ParseFiles(filename)
+	assert.Equal(t, "./fbsfiles/error_test/link_test35.fbs:2:3: field T.a: unknown type Unknown", err.Error())
+}
@@ -0,0 +1,188 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum number of suggestions given for an unknown type.
+const maxSuggestions = 3
+
+// Suggestion is a type which may be meant by an unknown type name.
+type Suggestion struct {
+	// Name is the fully qualified name of the type.
+	Name string
+	// File is the file defining the type if it is not the file referring to it.
+	File string
+	// Included tells whether the type is visible from the file referring to it, that is
+	// whether File is included, directly or not.
+	Included bool
+}
+
+// String returns the suggestion as it is shown in error messages. Example:
+//
+//	rpc.app.server.MyTable (defined in include_test1.fbs)
+func (s Suggestion) String() string {
+	switch {
+	case s.File == "":
+		return s.Name
+	case s.Included:
+		return s.Name + " (defined in " + s.File + ")"
+	default:
+		return s.Name + " (defined in " + s.File + ", which is not included)"
+	}
+}
+
+// candidate is a type which may be suggested, rank orders the candidates, the lower the better.
+type candidate struct {
+	Suggestion
+	rank int
+}
+
+// suggest looks for the types that may be meant by the unknown type name referred to from fd.
+// In order, these are the types with the same name in another namespace or in a file which is
+// not included, then the ones whose name only differs in case, then the ones whose name is
+// close in edit distance. Only the types of the allowed kinds are suggested, any type if none
+// is given. Example:
+//
+//	table Monster { pos:Vce3; }
+//	                    ^^^^ did you mean MyGame.Vec3?
+func (l *linker) suggest(fd *SchemaDesc, name string, allowed []string) []Suggestion {
+	short := name[strings.LastIndexByte(name, '.')+1:]
+	// Names shorter than 3 runes are only matched up to case, any other name would be close.
+	maxDistance := len([]rune(short)) / 3
+	included := l.includedFiles(fd)
+	var candidates []candidate
+	for _, filename := range l.filenames {
+		other := l.files[filename].fd
+		for fqn, d := range l.descPool[other] {
+			if !suggestable(d, allowed) {
+				continue
+			}
+			s := fqn[strings.LastIndexByte(fqn, '.')+1:]
+			var rank int
+			switch {
+			case s == short:
+				rank = 0
+			case strings.EqualFold(s, short):
+				rank = 1
+			default:
+				dist := editDistance(strings.ToLower(s), strings.ToLower(short))
+				if dist > maxDistance {
+					continue
+				}
+				rank = 1 + dist
+			}
+			c := candidate{Suggestion: Suggestion{Name: fqn, Included: included[other]}, rank: rank}
+			if other != fd {
+				c.File = other.Name
+			}
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank < candidates[j].rank
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	var suggestions []Suggestion
+	for _, c := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, c.Suggestion)
+	}
+	return suggestions
+}
+
+// includedFiles returns the set of files visible from fd, that is fd itself and the files it
+// includes, directly or not.
+func (l *linker) includedFiles(fd *SchemaDesc) map[*SchemaDesc]bool {
+	included := map[*SchemaDesc]bool{}
+	var visit func(fd *SchemaDesc)
+	visit = func(fd *SchemaDesc) {
+		if included[fd] {
+			return
+		}
+		included[fd] = true
+		for _, incl := range fd.Includes {
+			if res := l.include(fd, incl); res != nil {
+				visit(res.fd)
+			}
+		}
+	}
+	visit(fd)
+	return included
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions
+// of adjacent runes turning a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// d[i][j] is the distance between s[:i] and t[:j].
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// minInt returns the smallest of the given ints.
+func minInt(v int, vs ...int) int {
+	for _, w := range vs {
+		if w < v {
+			v = w
+		}
+	}
+	return v
+}
+
+// suggestable tells whether d is a type of one of the allowed kinds, see descType, or of any
+// kind if none is given.
+func suggestable(d Desc, allowed []string) bool {
+	switch d.(type) {
+	case *TableDesc, *StructDesc, *EnumDesc, *UnionDesc:
+	default:
+		return false
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	kind := descType(d)
+	for _, a := range allowed {
+		if a == kind {
+			return true
+		}
+	}
+	return false
+}
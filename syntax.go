@@ -0,0 +1,175 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import (
+	"fmt"
+
+	"trpc.group/trpc-go/fbs/ast"
+)
+
+func init() {
+	// Let the parser report the unexpected and expected tokens.
+	fbsErrorVerbose = true
+	for kw, tok := range keywords {
+		keywordNames[tokenName(tok)] = kw
+	}
+}
+
+// tokenName returns the name of token tok used by the parser.
+func tokenName(tok int) string {
+	if tok >= fbsPrivate && tok-fbsPrivate < len(fbsTok2) {
+		tok = int(fbsTok2[tok-fbsPrivate])
+	} else if tok >= 0 && tok < len(fbsTok1) {
+		tok = int(fbsTok1[tok])
+	}
+	return fbsTokname(tok)
+}
+
+// tokenDescriptions maps the names of tokens used by the parser to readable descriptions.
+var tokenDescriptions = map[string]string{
+	"$end":     "end of file",
+	"$unk":     "unknown token",
+	"Error":    "invalid token",
+	"Ident":    "identifier",
+	"StrLit":   "string literal",
+	"IntLit":   "integer literal",
+	"FloatLit": "floating point literal",
+}
+
+// keywordNames maps the names of keyword tokens used by the parser to the keywords.
+var keywordNames = map[string]string{}
+
+// tokenDescription returns the readable description of a token named by the parser.
+// Example: "Ident" => "identifier", "RPCService" => "'rpc_service'", "';'" => "';'".
+func tokenDescription(name string) string {
+	if d, ok := tokenDescriptions[name]; ok {
+		return d
+	}
+	if kw, ok := keywordNames[name]; ok {
+		return "'" + kw + "'"
+	}
+	return name
+}
+
+// declKeywords stores the keywords starting a declaration.
+var declKeywords = map[int]bool{
+	Include:        true,
+	Namespace:      true,
+	Attribute:      true,
+	Table:          true,
+	Struct:         true,
+	Enum:           true,
+	Union:          true,
+	RootType:       true,
+	FileExtension:  true,
+	FileIdentifier: true,
+	RPCService:     true,
+}
+
+// fieldPart is the part of a field being lexed.
+type fieldPart int
+
+const (
+	fieldNone     fieldPart = iota
+	fieldType               // after ':'
+	fieldDefault            // after '='
+	fieldMetadata           // after '('
+)
+
+// syntaxContext follows the tokens lexed to explain syntax errors.
+type syntaxContext struct {
+	// decl is the keyword token starting the current declaration and name its name.
+	decl int
+	name string
+	// depth is the depth of braces.
+	depth int
+	// field is the name of the field of a table or struct being lexed, part is the
+	// part of it being lexed.
+	field string
+	part  fieldPart
+	// prev is the last token followed and prevSym its node.
+	prev    int
+	prevSym ast.TerminalNode
+}
+
+// add follows token tok whose node is n.
+func (c *syntaxContext) add(tok int, n ast.TerminalNode) {
+	switch {
+	case tok == '{' || tok == '}' || tok == ';':
+		if tok == '{' {
+			c.depth++
+		} else if tok == '}' && c.depth > 0 {
+			c.depth--
+		}
+		c.field, c.part = "", fieldNone
+	case c.depth == 0 && declKeywords[tok]:
+		c.decl, c.name = tok, ""
+	case c.depth == 0 && tok == Ident && c.prev == c.decl:
+		c.name = n.RawText()
+	case c.depth == 1 && tok == ':' && c.part == fieldNone && c.prev == Ident && (c.decl == Table || c.decl == Struct):
+		c.field, c.part = c.prevSym.RawText(), fieldType
+	case c.field != "" && tok == '=':
+		c.part = fieldDefault
+	case c.field != "" && tok == '(':
+		c.part = fieldMetadata
+	}
+	c.prev, c.prevSym = tok, n
+}
+
+// syntaxError creates the error for the message of the parser, unexpected is the token
+// the parser failed at and n its node. Common mistakes are given targeted messages.
+// Examples:
+//
+//	table Monster { hp:short }
+//	                        ^ missing ';' after field hp
+//	enum Color { Red }
+//	           ^ enum Color: missing underlying type, such as enum Color : byte
+func (c *syntaxContext) syntaxError(msg string, unexpected int, n ast.TerminalNode) error {
+	e := newSyntaxError(msg)
+	if unexpected == Ident {
+		e.Unexpected = "identifier " + n.RawText()
+	}
+	var expectsSemicolon bool
+	for _, tok := range e.Expected {
+		expectsSemicolon = expectsSemicolon || tok == "';'"
+	}
+	var pos *ast.Position
+	switch {
+	case c.field != "" && expectsSemicolon && unexpected == ',':
+		e.Message = fmt.Sprintf("field %s must end with ';', not ','", c.field)
+	case c.field != "" && expectsSemicolon && c.prev != ':' && (unexpected == '}' || unexpected == Ident):
+		e.Message = fmt.Sprintf("missing ';' after field %s", c.field)
+		pos = c.prevSym.End() // where the ';' is missing.
+	case c.decl == Enum && c.depth == 0 && c.prev == Ident && unexpected == '{':
+		e.Message = fmt.Sprintf("enum %s: missing underlying type, such as enum %s : byte", c.name, c.name)
+	case (c.decl == Enum || c.decl == Union) && c.depth == 1 && unexpected == ';':
+		e.Message = fmt.Sprintf("values of %s %s must be separated by ',', not ';'",
+			keywordNames[tokenName(c.decl)], c.name)
+	case c.part == fieldType && c.prev == ':' && len(e.Expected) == 0:
+		e.Expected = []string{"field type"}
+	case c.part == fieldDefault && c.prev == '=' && len(e.Expected) == 0:
+		e.Expected = []string{"default value"}
+	case c.part == fieldType && c.prev != ':':
+		e.Context = "after field type"
+	case c.part == fieldDefault && c.prev != '=':
+		e.Context = "after default value"
+	case c.part == fieldMetadata && c.prev == ')':
+		e.Context = "after field attributes"
+	}
+	if pos == nil {
+		return e
+	}
+	return ErrorWithPos{Pos: pos, Err: e}
+}
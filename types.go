@@ -0,0 +1,179 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import "math"
+
+// BaseType enumerates the builtin types of flatbuffers.
+type BaseType int
+
+// Builtin types of flatbuffers. Aliases such as int8/uint8/float32 are mapped
+// to the same base type as byte/ubyte/float. Vector, table, struct and union are
+// the kinds of non-builtin field types, see FieldType.
+const (
+	BaseTypeNone BaseType = iota
+	BaseTypeBool
+	BaseTypeByte
+	BaseTypeUbyte
+	BaseTypeShort
+	BaseTypeUshort
+	BaseTypeInt
+	BaseTypeUint
+	BaseTypeLong
+	BaseTypeUlong
+	BaseTypeFloat
+	BaseTypeDouble
+	BaseTypeString
+	BaseTypeVector
+	BaseTypeTable
+	BaseTypeStruct
+	BaseTypeUnion
+)
+
+// baseTypes maps builtin type names to their base types.
+var baseTypes = map[string]BaseType{
+	"bool":    BaseTypeBool,
+	"byte":    BaseTypeByte,
+	"int8":    BaseTypeByte,
+	"ubyte":   BaseTypeUbyte,
+	"uint8":   BaseTypeUbyte,
+	"short":   BaseTypeShort,
+	"int16":   BaseTypeShort,
+	"ushort":  BaseTypeUshort,
+	"uint16":  BaseTypeUshort,
+	"int":     BaseTypeInt,
+	"int32":   BaseTypeInt,
+	"uint":    BaseTypeUint,
+	"uint32":  BaseTypeUint,
+	"long":    BaseTypeLong,
+	"int64":   BaseTypeLong,
+	"ulong":   BaseTypeUlong,
+	"uint64":  BaseTypeUlong,
+	"float":   BaseTypeFloat,
+	"float32": BaseTypeFloat,
+	"double":  BaseTypeDouble,
+	"float64": BaseTypeDouble,
+	"string":  BaseTypeString,
+}
+
+// baseTypeNames stores the canonical name of each base type.
+var baseTypeNames = map[BaseType]string{
+	BaseTypeNone:   "none",
+	BaseTypeBool:   "bool",
+	BaseTypeByte:   "byte",
+	BaseTypeUbyte:  "ubyte",
+	BaseTypeShort:  "short",
+	BaseTypeUshort: "ushort",
+	BaseTypeInt:    "int",
+	BaseTypeUint:   "uint",
+	BaseTypeLong:   "long",
+	BaseTypeUlong:  "ulong",
+	BaseTypeFloat:  "float",
+	BaseTypeDouble: "double",
+	BaseTypeString: "string",
+	BaseTypeVector: "vector",
+	BaseTypeTable:  "table",
+	BaseTypeStruct: "struct",
+	BaseTypeUnion:  "union",
+}
+
+// LookupBaseType returns the base type of a builtin type name such as "short" or "uint8".
+// BaseTypeNone is returned if name is not a builtin type.
+func LookupBaseType(name string) BaseType {
+	return baseTypes[name]
+}
+
+// String implements Stringer interface.
+func (t BaseType) String() string {
+	if s, ok := baseTypeNames[t]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// IsScalar reports whether t is a boolean, integer or floating point type.
+func (t BaseType) IsScalar() bool {
+	return t >= BaseTypeBool && t <= BaseTypeDouble
+}
+
+// IsInteger reports whether t is an integer type.
+func (t BaseType) IsInteger() bool {
+	return t >= BaseTypeByte && t <= BaseTypeUlong
+}
+
+// IsUnsigned reports whether t is an unsigned integer type.
+func (t BaseType) IsUnsigned() bool {
+	switch t {
+	case BaseTypeUbyte, BaseTypeUshort, BaseTypeUint, BaseTypeUlong:
+		return true
+	}
+	return false
+}
+
+// IsFloat reports whether t is a floating point type.
+func (t BaseType) IsFloat() bool {
+	return t == BaseTypeFloat || t == BaseTypeDouble
+}
+
+// Size returns the size in bytes of a scalar type, 0 for the others.
+func (t BaseType) Size() int {
+	switch t {
+	case BaseTypeBool, BaseTypeByte, BaseTypeUbyte:
+		return 1
+	case BaseTypeShort, BaseTypeUshort:
+		return 2
+	case BaseTypeInt, BaseTypeUint, BaseTypeFloat:
+		return 4
+	case BaseTypeLong, BaseTypeUlong, BaseTypeDouble:
+		return 8
+	}
+	return 0
+}
+
+// intRange returns the range of a signed integer type.
+func (t BaseType) intRange() (int64, int64) {
+	bits := uint(t.Size() * 8)
+	if bits == 64 {
+		return math.MinInt64, math.MaxInt64
+	}
+	return -1 << (bits - 1), 1<<(bits-1) - 1
+}
+
+// uintMax returns the maximum value of an unsigned integer type.
+func (t BaseType) uintMax() uint64 {
+	bits := uint(t.Size() * 8)
+	if bits == 64 {
+		return math.MaxUint64
+	}
+	return 1<<bits - 1
+}
+
+// FieldType describes the resolved type of a field. Fields of enum types have the
+// underlying type of the enum as their base type. Example:
+//
+//	inventory:[ubyte];  // BaseType: BaseTypeVector, ElementType: BaseTypeUbyte
+//	color:Color;        // BaseType: BaseTypeUbyte, given enum Color:ubyte
+//	enemy:Monster;      // BaseType: BaseTypeTable
+type FieldType struct {
+	BaseType    BaseType // BaseType is the kind of the field type, BaseTypeVector for vectors.
+	ElementType BaseType // ElementType is the kind of vector elements, BaseTypeNone for non-vectors.
+}
+
+// newFieldType creates the field type of t or a vector of t.
+func newFieldType(isVector bool, t BaseType) FieldType {
+	if isVector {
+		return FieldType{BaseType: BaseTypeVector, ElementType: t}
+	}
+	return FieldType{BaseType: t}
+}
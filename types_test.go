@@ -0,0 +1,55 @@
+//
+//
+// Tencent is pleased to support the open source community by making tRPC available.
+//
+// Copyright (C) 2023 THL A29 Limited, a Tencent company.
+// All rights reserved.
+//
+// If you have downloaded a copy of the tRPC source code from Tencent,
+// please note that tRPC source code is licensed under the Apache 2.0 License,
+// A copy of the Apache 2.0 License is included in this file.
+//
+//
+
+package fbs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseType(t *testing.T) {
+	assert.Equal(t, BaseTypeByte, LookupBaseType("int8"))
+	assert.Equal(t, BaseTypeUlong, LookupBaseType("uint64"))
+	assert.Equal(t, BaseTypeDouble, LookupBaseType("float64"))
+	assert.Equal(t, BaseTypeNone, LookupBaseType("MyTable"))
+	assert.Equal(t, "ushort", LookupBaseType("uint16").String())
+	assert.Equal(t, "unknown", BaseType(-1).String())
+	assert.True(t, BaseTypeBool.IsScalar())
+	assert.False(t, BaseTypeString.IsScalar())
+	assert.True(t, BaseTypeUint.IsInteger())
+	assert.True(t, BaseTypeUint.IsUnsigned())
+	assert.False(t, BaseTypeInt.IsUnsigned())
+	assert.True(t, BaseTypeFloat.IsFloat())
+	assert.Equal(t, 2, BaseTypeShort.Size())
+	assert.Equal(t, 0, BaseTypeString.Size())
+	mi, mx := BaseTypeByte.intRange()
+	assert.Equal(t, int64(math.MinInt8), mi)
+	assert.Equal(t, int64(math.MaxInt8), mx)
+	mi, mx = BaseTypeLong.intRange()
+	assert.Equal(t, int64(math.MinInt64), mi)
+	assert.Equal(t, int64(math.MaxInt64), mx)
+	assert.Equal(t, uint64(math.MaxUint16), BaseTypeUshort.uintMax())
+	assert.Equal(t, uint64(math.MaxUint64), BaseTypeUlong.uintMax())
+	assert.Equal(t, "vector", BaseTypeVector.String())
+	assert.False(t, BaseTypeTable.IsScalar())
+	assert.Equal(t, BaseTypeNone, LookupBaseType("vector"))
+}
+
+func TestFieldType(t *testing.T) {
+	assert.Equal(t, FieldType{BaseType: BaseTypeInt}, newFieldType(false, BaseTypeInt))
+	assert.Equal(t, FieldType{BaseType: BaseTypeVector, ElementType: BaseTypeTable},
+		newFieldType(true, BaseTypeTable))
+}